@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/internal/checks"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+// runChecks runs the configured checks against the files about to be
+// committed, offering to stage any changes made by formatters. It returns
+// false if a check failed or the user stopped them
+func runChecks(config cfg.Settings, theme *huh.Theme) bool {
+	if len(config.Checks) == 0 || noVerify {
+		return true
+	}
+	before, err := getStatus()
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	files := checkedPaths(before, all)
+
+	runner := &checks.Runner{Checks: config.Checks, Files: files, Catalogue: catalogue, Styles: newCheckStyles(theme, messages)}
+	var results []checks.Result
+	if accessible {
+		results = checks.RunWithLog(runner, os.Stderr)
+	} else {
+		results, err = checks.RunWithProgress(runner)
+		if errors.Is(err, checks.ErrAborted) {
+			return false
+		}
+		if err != nil {
+			fail(ErrorString, err)
+		}
+	}
+
+	if !all && hasFormatters(config.Checks) {
+		after, err := getStatus()
+		if err != nil {
+			fail(ErrorString, err)
+		}
+		if formatted := formattedPaths(before, after); len(formatted) > 0 {
+			offerToStage(formatted, theme)
+		}
+	}
+
+	failures := checks.Failures(results)
+	for _, r := range failures {
+		_, _ = fmt.Fprintf(os.Stderr, "\n%s\n%s\n", messages.errorf("%s", catalogue.T("checks.failed", r.Check.Label())), strings.TrimRight(r.Output, "\n"))
+	}
+	if len(failures) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "\n%s\n", messages.warnf("%s", catalogue.T("checks.fix")))
+		return false
+	}
+	return true
+}
+
+// checkedPaths returns the files the checks run against: the staged files,
+// or every changed tracked file when committing with --all
+func checkedPaths(files []fileStatus, all bool) []string {
+	paths := []string{}
+	for _, f := range files {
+		if f.Index == 'D' || f.Worktree == 'D' {
+			continue
+		}
+		if f.IsStaged() || (all && f.IsUnstaged() && !f.IsUntracked()) {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+func hasFormatters(c []cfg.Check) bool {
+	for _, check := range c {
+		if check.Formatter {
+			return true
+		}
+	}
+	return false
+}
+
+// formattedPaths returns the staged files that gained unstaged changes while
+// the checks ran. Files that already had unstaged changes are left out, as
+// staging them would also stage the user's own edits
+func formattedPaths(before, after []fileStatus) []string {
+	dirty := map[string]bool{}
+	for _, f := range before {
+		if f.IsUnstaged() {
+			dirty[f.Path] = true
+		}
+	}
+	paths := []string{}
+	for _, f := range after {
+		if f.IsStaged() && f.IsUnstaged() && !dirty[f.Path] {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// offerToStage asks whether to stage the changes the formatters made
+func offerToStage(paths []string, theme *huh.Theme) {
+	stage := true
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title(catalogue.T("checks.formatted")).
+				Description(strings.Join(paths, "\n")),
+			huh.NewConfirm().
+				Title(catalogue.T("checks.stage")).
+				Affirmative(catalogue.T("yes")).
+				Negative(catalogue.T("no")).
+				Value(&stage),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible).Run()
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	if stage {
+		if err := stageFiles(paths); err != nil {
+			fail(ErrorString, err)
+		}
+	}
+}
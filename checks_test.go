@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckedPaths(t *testing.T) {
+	files := parseStatus("M  staged.go\x00 M unstaged.go\x00?? new.go\x00D  deleted.go\x00MM both.go\x00")
+	cases := []struct {
+		Desc string
+		all  bool
+		want string
+	}{
+		{"it should check the staged files", false, "staged.go,both.go"},
+		{"it should check every changed tracked file with --all", true, "staged.go,unstaged.go,both.go"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, strings.Join(checkedPaths(files, tc.all), ","))
+		})
+	}
+}
+
+func TestFormattedPaths(t *testing.T) {
+	before := parseStatus("M  formatted.go\x00MM edited.go\x00M  untouched.go\x00")
+	after := parseStatus("MM formatted.go\x00MM edited.go\x00M  untouched.go\x00")
+	assertEqualStrings(t, "formatted.go", strings.Join(formattedPaths(before, after), ","))
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/alessio/shellescape"
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/internal/wizard"
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+const fixupHistoryLimit = 50
+
+// runFixup creates a fixup!, squash! or amend! commit for a commit on the
+// current branch and optionally autosquashes it straight away
+func runFixup(config cfg.Settings, theme *huh.Theme, args []string) {
+	// on the default branch itself every commit is on the branch
+	mergeBase := ""
+	if base := defaultBranch(); base != "" && strings.TrimPrefix(base, "origin/") != currentBranch() {
+		mergeBase, _ = getMergeBase(base)
+	}
+	entries, err := getLog(fixupLogArgs(mergeBase)...)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	if len(entries) == 0 {
+		fail(ErrorString, "there are no commits on this branch to fix up")
+	}
+
+	if shouldStage(args) {
+		runStaging(theme)
+	}
+
+	var target string
+	kind := "fixup"
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(catalogue.T("fixup.commit.title")).
+				Description(catalogue.T("fixup.commit.description")).
+				Options(fixupOptions(config.Commit(), entries)...).
+				Height(12).
+				Value(&target),
+			huh.NewSelect[string]().
+				Title(catalogue.T("fixup.kind.title")).
+				Description(catalogue.T("fixup.kind.description")).
+				Options(
+					huh.NewOption(catalogue.T("fixup.kind.fixup"), "fixup"),
+					huh.NewOption(catalogue.T("fixup.kind.squash"), "squash"),
+					huh.NewOption(catalogue.T("fixup.kind.amend"), "amend"),
+				).
+				Value(&kind),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible)
+	if err := form.Run(); err != nil {
+		fail(ErrorString, err)
+	}
+
+	var entry logEntry
+	for _, e := range entries {
+		if e.Hash == target {
+			entry = e
+		}
+	}
+
+	rawCommitCommand, printableCommitCommand, doesWantToCommit := fixupCommand(config, theme, kind, entry, args)
+	if !doesWantToCommit {
+		commitAborted(printableCommitCommand)
+		return
+	}
+	if err := commit(rawCommitCommand); err != nil {
+		commitFailed(printableCommitCommand, err)
+	}
+
+	doesWantToAutosquash := false
+	if err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(catalogue.T("fixup.autosquash.title")).
+				Description(catalogue.T("fixup.autosquash.description")).
+				Affirmative(catalogue.T("confirm.yes")).
+				Negative(catalogue.T("fixup.autosquash.no")).
+				Value(&doesWantToAutosquash),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible).Run(); err != nil {
+		fail(ErrorString, err)
+	}
+	if doesWantToAutosquash {
+		if err := autosquash(entry.Hash); err != nil {
+			fail(
+				"\n%s\n%s\n\n",
+				messages.errorf("%s", catalogue.T("fixup.autosquash.failed", err)),
+				messages.warnf("%s", catalogue.T("rebase.stopped")),
+			)
+		}
+	}
+}
+
+// fixupLogArgs returns the git log arguments for the commits that can be
+// fixed up: those since the branch forked from the merge base, or the most
+// recent ones when there is no base branch
+func fixupLogArgs(mergeBase string) []string {
+	args := []string{"--no-merges", "-n", fmt.Sprint(fixupHistoryLimit)}
+	if mergeBase == "" {
+		return args
+	}
+	return append(args, mergeBase+"..HEAD")
+}
+
+// fixupCommand builds the commit command for the chosen kind of fixup,
+// asking for a message where the kind needs one
+func fixupCommand(config cfg.Settings, theme *huh.Theme, kind string, entry logEntry, args []string) ([]string, string, bool) {
+	switch kind {
+	case "squash":
+		var body string
+		doesWantToCommit := true
+		form := wizard.MessageForm(wizard.Config{Settings: config, Catalogue: catalogue}, nil, &body).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible)
+		if err := form.Run(); err != nil {
+			fail(ErrorString, err)
+		}
+		if err := confirmForm(&doesWantToCommit).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible).Run(); err != nil {
+			fail(ErrorString, err)
+		}
+		raw, printable := buildCommitCommand("squash! "+entry.Subject(), body, args)
+		return raw, printable, doesWantToCommit
+	case "amend":
+		prefill, err := cmt.Parse(config.Commit(), entry.Message)
+		if err != nil && !errors.Is(err, cmt.ErrNoMatch) {
+			fail(ErrorString, err)
+		}
+		state, err := newWizard(config, prefill).Run(wizard.Interactive{Theme: theme})
+		if err != nil {
+			fail(ErrorString, err)
+		}
+		message := strings.TrimSpace(state.Subject + "\n\n" + state.Body)
+		raw, printable := buildCommitCommand("amend! "+entry.Subject(), message, args)
+		return raw, printable, state.Confirmed
+	default:
+		raw := append([]string{"commit", "--fixup=" + entry.Hash}, args...)
+		return raw, fmt.Sprintf("git %v", shellescape.QuoteCommand(raw)), true
+	}
+}
+
+// fixupOptions returns an option for each commit showing its short hash, type,
+// scope and message in columns
+func fixupOptions(c cmt.Config, entries []logEntry) []huh.Option[string] {
+	options := make([]huh.Option[string], len(entries))
+	for i, e := range entries {
+		parsed, err := cmt.Parse(c, e.Subject())
+		if err != nil {
+			parsed = cmt.Commit{Message: e.Subject()}
+		}
+		scope := parsed.Scope
+		if parsed.TicketNumber != "" {
+			scope = strings.TrimSpace(parsed.TicketNumber + " " + scope)
+		}
+		label := fmt.Sprintf("%.7s  %-10s %-16s %s", e.Hash, parsed.Type, scope, parsed.Message)
+		options[i] = huh.NewOption(label, e.Hash)
+	}
+	return options
+}
+
+// confirmForm returns the "Ready to commit?" form
+func confirmForm(value *bool) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(catalogue.T("confirm.title")).
+				Affirmative(catalogue.T("confirm.yes")).
+				Negative(catalogue.T("confirm.no")).
+				Value(value),
+		),
+	)
+}
+
+// autosquash folds any fixup commits into the history after the given commit
+// without opening the rebase todo list
+func autosquash(sha string) error {
+	args := append([]string{"rebase", "--interactive", "--autosquash", "--autostash"}, rebaseBase(sha)...)
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_SEQUENCE_EDITOR=:")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestFixupOptions(t *testing.T) {
+	entries := []logEntry{
+		{Hash: "1a2b3c4d5e6f", Message: "feat(api): add pagination\n\nwith a body"},
+		{Hash: "0f9e8d7c6b5a", Message: "COMP-12: <fix> handle nil"},
+		{Hash: "abcdef012345", Message: "Merge branch 'main'"},
+	}
+	cases := []struct {
+		Desc      string
+		wantKey   string
+		wantValue string
+	}{
+		{"it should show type and scope", "1a2b3c4  feat       api              add pagination", "1a2b3c4d5e6f"},
+		{"it should show the ticket with the scope", "0f9e8d7  fix        COMP-12          handle nil", "0f9e8d7c6b5a"},
+		{"it should show unconventional subjects as they are", "abcdef0                              Merge branch 'main'", "abcdef012345"},
+	}
+
+	got := fixupOptions(cfg.Default().Commit(), entries)
+	for i, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.wantKey, got[i].Key)
+			assertEqualStrings(t, tc.wantValue, got[i].Value)
+		})
+	}
+}
+
+func TestFixupLogArgs(t *testing.T) {
+	cases := []struct {
+		Desc      string
+		mergeBase string
+		want      string
+	}{
+		{"it should only list the commits on the branch", "abc123", "--no-merges -n 50 abc123..HEAD"},
+		{"it should list the recent commits without a base branch", "", "--no-merges -n 50"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, strings.Join(fixupLogArgs(tc.mergeBase), " "))
+		})
+	}
+}
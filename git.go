@@ -3,10 +3,14 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alessio/shellescape"
 )
@@ -30,6 +34,16 @@ func findGitDir(gitPath string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// getGitDir returns the git directory shared by every worktree of the
+// repository
+func getGitDir() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not find the git directory: %w", err)
+	}
+	return filepath.Abs(strings.TrimSpace(string(out)))
+}
+
 func checkBoardMatchesBranch(board string, msg string) bool {
 	match, _ := regexp.MatchString(fmt.Sprintf(`(?i)%s-\d{1,}`, board), msg)
 	return match
@@ -89,8 +103,46 @@ func getComitters(osArgs []string) ([]string, error) {
 	return result, nil
 }
 
+// commitError is a failed commit with what git printed to stderr
+type commitError struct {
+	err    error
+	Stderr string
+}
+
+func (e *commitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *commitError) Unwrap() error {
+	return e.err
+}
+
 // commit commits the changes to git
 func commit(command []string) error {
+	cmd := exec.Command("git", command...)
+	var stderr bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+
+	if err := cmd.Run(); err != nil {
+		return &commitError{err: err, Stderr: stderr.String()}
+	}
+	return nil
+}
+
+// getGitConfig returns a git config value, or an empty string if it is not
+// set. Options such as "--bool" can come before the key
+func getGitConfig(args ...string) string {
+	out, err := exec.Command("git", append([]string{"config", "--get"}, args...)...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// runGit runs a git command attached to the terminal
+func runGit(command []string) error {
 	cmd := exec.Command("git", command...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
@@ -115,3 +167,326 @@ func getOutput(command []string) ([]string, error) {
 	}
 	return strings.Split(buf.String(), "\n"), nil
 }
+
+// getCommitMessage returns the full message of the given revision
+func getCommitMessage(rev string) (string, error) {
+	out, err := exec.Command("git", "log", "-1", "--format=%B", rev).Output()
+	if err != nil {
+		return "", fmt.Errorf("could not read the message of %s: %w", rev, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// getStagedDiff returns a summary of the staged changes followed by the full
+// diff
+func getStagedDiff() (string, error) {
+	stat, err := exec.Command("git", "diff", "--cached", "--stat", "--no-color").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not read the staged changes: %w", err)
+	}
+	diff, err := exec.Command("git", "diff", "--cached", "--no-color").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not read the staged changes: %w", err)
+	}
+	if len(diff) == 0 {
+		return catalogue.T("diff.empty"), nil
+	}
+	return string(stat) + "\n" + strings.TrimRight(string(diff), "\n"), nil
+}
+
+// getCommitDiff returns the changes that are about to be committed, without
+// context lines or path prefixes, whatever diff.noprefix and
+// diff.mnemonicPrefix are set to. With all set, unstaged changes to tracked
+// files are included
+func getCommitDiff(all bool) (string, error) {
+	base := "--cached"
+	if all {
+		base = "HEAD"
+	}
+	out, err := exec.Command("git", "diff", base, "--unified=0", "--no-color", "--no-ext-diff", "--no-prefix").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not read the changes to commit: %w", err)
+	}
+	return string(out), nil
+}
+
+// resolveCommit returns the full hash of the commit the revision points to
+func resolveCommit(rev string) (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}").Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not a commit", rev)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// isAncestorOfHead reports whether the commit is reachable from HEAD
+func isAncestorOfHead(sha string) bool {
+	return exec.Command("git", "merge-base", "--is-ancestor", sha, "HEAD").Run() == nil
+}
+
+// logEntry is a single commit read from git log
+type logEntry struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// Subject returns the first line of the commit message
+func (e logEntry) Subject() string {
+	subject, _, _ := strings.Cut(e.Message, "\n")
+	return subject
+}
+
+// getLog returns the commits git log lists for the given arguments
+func getLog(osArgs ...string) ([]logEntry, error) {
+	args := append([]string{"log", "--format=%H%x1f%an <%ae>%x1f%aI%x1f%B%x1e"}, osArgs...)
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the git log: %w", err)
+	}
+	return parseLog(string(out)), nil
+}
+
+// parseLog parses the output of git log in the format used by getLog
+func parseLog(out string) []logEntry {
+	entries := []logEntry{}
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		entries = append(entries, logEntry{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    date,
+			Message: strings.TrimRight(fields[3], "\n"),
+		})
+	}
+	return entries
+}
+
+// rebaseBase returns the arguments for an interactive rebase that starts just
+// before the given commit
+func rebaseBase(sha string) []string {
+	if exec.Command("git", "rev-parse", "--verify", "--quiet", sha+"^").Run() == nil {
+		return []string{sha + "^"}
+	}
+	return []string{"--root"}
+}
+
+// revertWithoutCommit applies the inverse of the commit to the index and
+// working tree without committing it
+func revertWithoutCommit(sha string) error {
+	cmd := exec.Command("git", "revert", "--no-commit", sha)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// fileStatus is the state of a single path as reported by git status
+type fileStatus struct {
+	Path     string
+	Index    byte
+	Worktree byte
+}
+
+// IsStaged reports whether the path has changes in the index
+func (f fileStatus) IsStaged() bool {
+	return f.Index != ' ' && f.Index != '?' && f.Index != '!'
+}
+
+// IsUnstaged reports whether the path has changes in the working tree, or is
+// untracked
+func (f fileStatus) IsUnstaged() bool {
+	return f.Worktree != ' ' && f.Worktree != '!'
+}
+
+// IsUntracked reports whether git does not know about the path yet
+func (f fileStatus) IsUntracked() bool {
+	return f.Index == '?'
+}
+
+// getStatus returns the status of every changed path in the repository
+func getStatus() ([]fileStatus, error) {
+	out, err := exec.Command("git", "status", "--porcelain=v1", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the git status: %w", err)
+	}
+	return parseStatus(string(out)), nil
+}
+
+// parseStatus parses the output of git status --porcelain=v1 -z
+func parseStatus(out string) []fileStatus {
+	files := []fileStatus{}
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		f := fileStatus{Index: entry[0], Worktree: entry[1], Path: entry[3:]}
+		// renames and copies are followed by the original path
+		if f.Index == 'R' || f.Index == 'C' {
+			i++
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// stageFiles adds the paths to the index
+func stageFiles(paths []string) error {
+	args := append([]string{"add", "--"}, paths...)
+	return runGit(args)
+}
+
+// stageTrackedFiles adds the changes to every tracked file to the index, as
+// git commit --all would
+func stageTrackedFiles() error {
+	return runGit([]string{"add", "--update"})
+}
+
+// unstageFiles removes the paths from the index, leaving the working tree
+// alone
+func unstageFiles(paths []string) error {
+	args := append([]string{"reset", "--quiet", "--"}, paths...)
+	if _, err := resolveCommit("HEAD"); err != nil {
+		// there is nothing to reset to before the first commit
+		args = append([]string{"rm", "--cached", "--quiet", "--"}, paths...)
+	}
+	return runGit(args)
+}
+
+// stagedFile is a file added or changed in the commit
+type stagedFile struct {
+	Path   string
+	Size   int64
+	Binary bool
+	// LFS is set when .gitattributes tracks the path with Git LFS
+	LFS bool
+	// Worktree is set when the size is of the file in the working tree
+	// rather than the staged blob, which for an LFS file is its content
+	// rather than the pointer
+	Worktree bool
+}
+
+// getCommitFiles returns every file added or changed in the index with its
+// staged size and attributes. With all set, changes to tracked files that
+// aren't staged are included, with their size in the working tree
+func getCommitFiles(all bool) ([]stagedFile, error) {
+	base := "--cached"
+	if all {
+		base = "HEAD"
+		if _, err := resolveCommit("HEAD"); err != nil {
+			// compare with the empty tree before the first commit
+			base = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+		}
+	}
+	out, err := exec.Command("git", "diff", base, "--numstat", "-z", "--no-renames", "--diff-filter=ACM").Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not list the staged files: %w", err)
+	}
+	files := parseNumstat(string(out))
+	if len(files) == 0 {
+		return files, nil
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+
+	if all {
+		root, err := findGitDir("git")
+		if err != nil {
+			return nil, err
+		}
+		for i := range files {
+			info, err := os.Stat(filepath.Join(root, files[i].Path))
+			if err != nil {
+				return nil, fmt.Errorf("could not read the file sizes: %w", err)
+			}
+			files[i].Size = info.Size()
+			files[i].Worktree = true
+		}
+	} else {
+		objects := ""
+		for _, path := range paths {
+			objects += ":" + path + "\n"
+		}
+		cmd := exec.Command("git", "cat-file", "--batch-check=%(objectsize)")
+		cmd.Stdin = strings.NewReader(objects)
+		out, err = cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("could not read the staged file sizes: %w", err)
+		}
+		for i, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+			if i < len(files) {
+				files[i].Size, _ = strconv.ParseInt(line, 10, 64)
+			}
+		}
+	}
+
+	args := []string{"check-attr", "-z", "--stdin", "filter"}
+	if !all {
+		args = []string{"check-attr", "--cached", "-z", "--stdin", "filter"}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	out, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the git attributes: %w", err)
+	}
+	lfs := parseLFSAttributes(string(out))
+	for i := range files {
+		files[i].LFS = lfs[files[i].Path]
+	}
+	return files, nil
+}
+
+// parseNumstat parses the output of git diff --numstat -z, where binary files
+// have "-" for their line counts
+func parseNumstat(out string) []stagedFile {
+	files := []stagedFile{}
+	for _, entry := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(entry, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		files = append(files, stagedFile{Path: fields[2], Binary: fields[0] == "-" && fields[1] == "-"})
+	}
+	return files
+}
+
+// parseLFSAttributes returns the paths whose filter attribute is lfs from the
+// output of git check-attr -z
+func parseLFSAttributes(out string) map[string]bool {
+	lfs := map[string]bool{}
+	fields := strings.Split(out, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+1] == "filter" && fields[i+2] == "lfs" {
+			lfs[fields[i]] = true
+		}
+	}
+	return lfs
+}
+
+// stageHunks lets the user pick the hunks of the paths to add to the index
+func stageHunks(paths []string) error {
+	untracked := []string{}
+	for _, path := range paths {
+		if exec.Command("git", "ls-files", "--error-unmatch", "--", path).Run() != nil {
+			untracked = append(untracked, path)
+		}
+	}
+	if len(untracked) > 0 {
+		if err := runGit(append([]string{"add", "--intent-to-add", "--"}, untracked...)); err != nil {
+			return err
+		}
+	}
+	args := append([]string{"add", "--patch", "--"}, paths...)
+	return runGit(args)
+}
@@ -56,6 +56,24 @@ func TestGetTicketNumberFromString(t *testing.T) {
 	}
 }
 
+func TestParseLog(t *testing.T) {
+	out := "aaa\x1fJane <jane@example.com>\x1f2024-03-01T10:00:00+00:00\x1ffeat: one\n\nbody\n\x1e\n" +
+		"bbb\x1fBob <bob@example.com>\x1f2024-03-02T10:00:00+00:00\x1ffix: two\n\x1e\n"
+
+	got := parseLog(out)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	assertEqualStrings(t, "aaa", got[0].Hash)
+	assertEqualStrings(t, "Jane <jane@example.com>", got[0].Author)
+	assertEqualStrings(t, "feat: one\n\nbody", got[0].Message)
+	assertEqualStrings(t, "feat: one", got[0].Subject())
+	assertEqualStrings(t, "fix: two", got[1].Message)
+	if got[1].Date.Day() != 2 {
+		t.Errorf("expected the date to be parsed, got %s", got[1].Date)
+	}
+}
+
 func assertEqualStrings(t testing.TB, expected, got string) {
 	t.Helper()
 	if got != expected {
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+
+	"github.com/stefanlogue/meteor/internal/util"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+// lfsPointerMaxSize is the largest a Git LFS pointer file can be
+const lfsPointerMaxSize = 1024
+
+// violation is a staged file that breaks one of the guard's rules
+type violation struct {
+	Path   string
+	Reason string
+}
+
+func (v violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Reason)
+}
+
+// checkGuard returns the staged files that break the guard's rules, at most
+// once per file
+func checkGuard(g cfg.Guard, files []stagedFile) []violation {
+	violations := []violation{}
+	for _, f := range files {
+		if reason := guardReason(g, f); reason != "" {
+			violations = append(violations, violation{Path: f.Path, Reason: reason})
+		}
+	}
+	return violations
+}
+
+func guardReason(g cfg.Guard, f stagedFile) string {
+	for _, glob := range g.Forbidden {
+		if util.MatchGlob(glob, f.Path) {
+			return catalogue.T("guard.forbidden", glob)
+		}
+	}
+	// only a staged blob is the pointer, a file in the working tree holds
+	// the content git-lfs stores
+	if g.CheckLFS() && f.LFS && !f.Worktree && f.Size > lfsPointerMaxSize {
+		return catalogue.T("guard.lfs")
+	}
+	if g.MaxFileSize > 0 && f.Size > int64(g.MaxFileSize) && !f.LFS {
+		return catalogue.T("guard.tooLarge", cfg.ByteSize(f.Size), g.MaxFileSize)
+	}
+	if f.Binary && !f.LFS && g.BinaryPaths != nil && !matchesAny(g.BinaryPaths, f.Path) {
+		return catalogue.T("guard.binary")
+	}
+	return ""
+}
+
+func matchesAny(globs []string, path string) bool {
+	for _, glob := range globs {
+		if util.MatchGlob(glob, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// runGuard checks the files about to be committed and lets the user unstage
+// the offending ones. With all set, the other tracked changes are staged
+// instead of being committed with --all, which would add the files back. It
+// returns false if the user chose to abort
+func runGuard(g cfg.Guard, theme *huh.Theme, all bool) (ok bool, staged bool) {
+	files, err := getCommitFiles(all)
+	if err != nil {
+		log.Error("Skipping the staged file checks", "error", err)
+		return true, false
+	}
+	violations := checkGuard(g, files)
+	if len(violations) == 0 {
+		return true, false
+	}
+
+	lines := make([]string, len(violations))
+	paths := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+		paths[i] = v.Path
+	}
+	action := "unstage"
+	err = huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title(catalogue.T("guard.title")).
+				Description(strings.Join(lines, "\n")),
+			huh.NewSelect[string]().
+				Title(catalogue.T("guard.action")).
+				Options(
+					huh.NewOption(catalogue.T("guard.unstage"), "unstage"),
+					huh.NewOption(catalogue.T("guard.abort"), "abort"),
+				).
+				Value(&action),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible).Run()
+	if err != nil || action == "abort" {
+		return false, false
+	}
+
+	if all {
+		if err := stageTrackedFiles(); err != nil {
+			fail(ErrorString, err)
+		}
+	}
+	if err := unstageFiles(paths); err != nil {
+		fail(ErrorString, err)
+	}
+	if len(paths) == len(files) {
+		fail(ErrorString, "there is nothing left to commit")
+	}
+	return true, all
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestCheckGuard(t *testing.T) {
+	lfs := false
+	tests := []struct {
+		name  string
+		guard cfg.Guard
+		files []stagedFile
+		want  []violation
+	}{
+		{
+			name:  "nothing configured only checks LFS",
+			guard: cfg.Guard{},
+			files: []stagedFile{
+				{Path: "big.bin", Size: 1 << 30, Binary: true},
+				{Path: "video.mp4", Size: 1 << 20, Binary: true, LFS: true},
+				{Path: "pointer.mp4", Size: 130, LFS: true},
+			},
+			want: []violation{{Path: "video.mp4", Reason: "should be stored in Git LFS, but is staged as a regular file"}},
+		},
+		{
+			name:  "LFS files in the working tree hold their content",
+			guard: cfg.Guard{},
+			files: []stagedFile{{Path: "video.mp4", Size: 1 << 20, Binary: true, LFS: true, Worktree: true}},
+			want:  []violation{},
+		},
+		{
+			name:  "LFS check can be turned off",
+			guard: cfg.Guard{LFS: &lfs},
+			files: []stagedFile{{Path: "video.mp4", Size: 1 << 20, Binary: true, LFS: true}},
+			want:  []violation{},
+		},
+		{
+			name:  "forbidden globs",
+			guard: cfg.Guard{Forbidden: []string{"*.env", "dist/**"}},
+			files: []stagedFile{{Path: "config/.env"}, {Path: "dist/js/app.js"}, {Path: "src/dist.go"}},
+			want: []violation{
+				{Path: "config/.env", Reason: `matches the forbidden pattern "*.env"`},
+				{Path: "dist/js/app.js", Reason: `matches the forbidden pattern "dist/**"`},
+			},
+		},
+		{
+			name:  "maximum size",
+			guard: cfg.Guard{MaxFileSize: 1 << 20},
+			files: []stagedFile{{Path: "data.json", Size: 3 << 20}, {Path: "small.json", Size: 10}},
+			want:  []violation{{Path: "data.json", Reason: "is 3.0MB, over the 1.0MB limit"}},
+		},
+		{
+			name:  "binary paths",
+			guard: cfg.Guard{BinaryPaths: []string{"assets/**"}},
+			files: []stagedFile{{Path: "assets/logo.png", Binary: true}, {Path: "logo.png", Binary: true}, {Path: "main.go"}},
+			want:  []violation{{Path: "logo.png", Reason: "is a binary file outside the allowed paths"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkGuard(tt.guard, tt.files)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	got := parseNumstat("3\t1\tmain.go\x00-\t-\tlogo.png\x00")
+	want := []stagedFile{{Path: "main.go"}, {Path: "logo.png", Binary: true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseLFSAttributes(t *testing.T) {
+	got := parseLFSAttributes("video.mp4\x00filter\x00lfs\x00main.go\x00filter\x00unspecified\x00")
+	want := map[string]bool{"video.mp4": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
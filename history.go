@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/stefanlogue/meteor/internal/wizard"
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/history"
+)
+
+// logMessagesLimit is the number of commits read for suggestions from the
+// git log
+const logMessagesLimit = 500
+
+// historyPath returns where the repository's message history is kept
+func historyPath() (string, error) {
+	gitDir, err := getGitDir()
+	if err != nil {
+		return "", err
+	}
+	return history.Path(gitDir), nil
+}
+
+// loadHistory returns the messages written before in the repository, or
+// nothing when the history is turned off or can't be read
+func loadHistory(c cfg.History) []history.Entry {
+	if !c.IsEnabled() {
+		return nil
+	}
+	path, err := historyPath()
+	if err != nil {
+		log.Debug("Not suggesting earlier messages", "error", err)
+		return nil
+	}
+	entries, err := history.Load(path)
+	if err != nil {
+		log.Error("Not suggesting earlier messages", "error", err)
+		return nil
+	}
+	return entries
+}
+
+// rememberMessage adds the message written in the wizard to the history, once
+// it has been committed
+func rememberMessage(c cfg.History, state *wizard.State) {
+	if !c.IsEnabled() || state.Commit.Message == "" {
+		return
+	}
+	path, err := historyPath()
+	if err != nil {
+		log.Debug("Not remembering the message", "error", err)
+		return
+	}
+	entries, err := history.Load(path)
+	if err != nil {
+		log.Error("Not remembering the message", "error", err)
+		return
+	}
+	entries = history.Add(entries, history.Entry{
+		Type:    state.Commit.Type,
+		Scope:   state.Commit.Scope,
+		Message: state.Commit.Message,
+		Body:    state.Commit.Body,
+		Time:    time.Now(),
+	}, c.Limit)
+	if err := history.Save(path, entries); err != nil {
+		log.Error("Not remembering the message", "error", err)
+	}
+}
+
+// logMessages returns a function listing the messages of recent commits in
+// a scope, or nil when the config doesn't ask for them. The log is read the
+// first time it is needed
+func logMessages(config cfg.Settings) func(scope string) []string {
+	if !config.History.IsEnabled() || !config.History.GitLog {
+		return nil
+	}
+	var entries []logEntry
+	read := false
+	return func(scope string) []string {
+		if !read {
+			read = true
+			var err error
+			if entries, err = getLog("--max-count=" + strconv.Itoa(logMessagesLimit)); err != nil {
+				log.Debug("Not suggesting messages from the git log", "error", err)
+			}
+		}
+		return scopeMessages(config.Commit(), entries, scope)
+	}
+}
+
+// scopeMessages returns the messages of the conventional commits in a scope
+func scopeMessages(c cmt.Config, entries []logEntry, scope string) []string {
+	messages := []string{}
+	for _, e := range entries {
+		parsed, err := cmt.Parse(c, e.Subject())
+		if err != nil || parsed.Scope != scope {
+			continue
+		}
+		messages = append(messages, parsed.Message)
+	}
+	return messages
+}
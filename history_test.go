@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestScopeMessages(t *testing.T) {
+	c := cmt.Config{MessageTemplate: cfg.DefaultMessageTemplate}
+	entries := []logEntry{
+		{Message: "chore(deps): bump foo from 1.0 to 1.1"},
+		{Message: "feat(api): add the export endpoint"},
+		{Message: "Merge branch 'main'"},
+		{Message: "chore(deps): bump bar from 2.0 to 3.0\n\nBreaking upstream."},
+		{Message: "fix: handle empty input"},
+	}
+	cases := []struct {
+		Desc  string
+		scope string
+		want  string
+	}{
+		{"it should list the messages in the scope", "deps", "bump foo from 1.0 to 1.1,bump bar from 2.0 to 3.0"},
+		{"it should list the messages without a scope", "", "handle empty input"},
+		{"it should list nothing for an unused scope", "web", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, strings.Join(scopeMessages(c, entries, tc.scope), ","))
+		})
+	}
+}
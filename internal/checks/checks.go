@@ -0,0 +1,170 @@
+// Package checks runs the project's configured checks against the staged
+// files.
+package checks
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alessio/shellescape"
+
+	"github.com/stefanlogue/meteor/internal/i18n"
+	"github.com/stefanlogue/meteor/internal/util"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+// filesPlaceholder is replaced with the files a check applies to
+const filesPlaceholder = "{files}"
+
+// Status is the state of a check
+type Status int
+
+const (
+	Pending Status = iota
+	Running
+	Passed
+	Failed
+	Skipped
+)
+
+// Result is the outcome of running a check
+type Result struct {
+	Check    config.Check
+	Status   Status
+	Output   string
+	Duration time.Duration
+}
+
+// Runner runs checks against a set of staged files
+type Runner struct {
+	Checks []config.Check
+	Files  []string
+	// Concurrency is the most checks run at once, or 0 for one per CPU
+	Concurrency int
+	// Shell runs the commands, defaulting to "sh"
+	Shell string
+	// Catalogue is the text of the progress, or nil for English
+	Catalogue *i18n.Catalogue
+	// Styles colour the progress, or are nil for the defaults
+	Styles *Styles
+}
+
+// text returns the text with the given id from the catalogue
+func (r *Runner) text(id string, args ...any) string {
+	if r.Catalogue == nil {
+		return i18n.English().T(id, args...)
+	}
+	return r.Catalogue.T(id, args...)
+}
+
+// Files returns the staged files a check applies to
+func Files(c config.Check, files []string) []string {
+	if len(c.Paths) == 0 {
+		return files
+	}
+	matched := []string{}
+	for _, f := range files {
+		for _, glob := range c.Paths {
+			if util.MatchGlob(glob, f) {
+				matched = append(matched, f)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Command returns the shell command for a check with the files filled in
+func Command(c config.Check, files []string) string {
+	return strings.ReplaceAll(c.Run, filesPlaceholder, shellescape.QuoteCommand(files))
+}
+
+// Run runs the formatters one at a time, then the other checks concurrently.
+// update is called from the running goroutine whenever a check starts or
+// finishes
+func (r *Runner) Run(ctx context.Context, update func(i int, result Result)) []Result {
+	results := make([]Result, len(r.Checks))
+	for i, c := range r.Checks {
+		results[i] = Result{Check: c, Status: Pending}
+	}
+	var mu sync.Mutex
+	set := func(i int, result Result) {
+		mu.Lock()
+		results[i] = result
+		mu.Unlock()
+		if update != nil {
+			update(i, result)
+		}
+	}
+
+	for i, c := range r.Checks {
+		if c.Formatter {
+			r.run(ctx, i, c, set)
+		}
+	}
+
+	limit := r.Concurrency
+	if limit <= 0 {
+		limit = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, c := range r.Checks {
+		if c.Formatter {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, c config.Check) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			r.run(ctx, i, c, set)
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+func (r *Runner) run(ctx context.Context, i int, c config.Check, set func(int, Result)) {
+	files := Files(c, r.Files)
+	if len(c.Paths) > 0 && len(files) == 0 {
+		set(i, Result{Check: c, Status: Skipped})
+		return
+	}
+	set(i, Result{Check: c, Status: Running})
+
+	shell := r.Shell
+	if shell == "" {
+		shell = "sh"
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, shell, "-c", Command(c, files))
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	start := time.Now()
+	err := cmd.Run()
+	result := Result{Check: c, Status: Passed, Output: out.String(), Duration: time.Since(start)}
+	if err != nil {
+		result.Status = Failed
+		if result.Output == "" {
+			result.Output = err.Error()
+		}
+	}
+	set(i, result)
+}
+
+// Failures returns the checks that failed
+func Failures(results []Result) []Result {
+	failed := []Result{}
+	for _, r := range results {
+		if r.Status == Failed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
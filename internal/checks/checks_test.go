@@ -0,0 +1,109 @@
+package checks
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestFiles(t *testing.T) {
+	files := []string{"main.go", "web/app.ts", "README.md"}
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"no paths applies to every file", nil, files},
+		{"globs filter the files", []string{"*.go", "*.md"}, []string{"main.go", "README.md"}},
+		{"no matches", []string{"*.py"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Files(config.Check{Paths: tt.paths}, files)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	c := config.Check{Run: "gofmt -l {files}"}
+	got := Command(c, []string{"main.go", "my file.go"})
+	want := "gofmt -l main.go 'my file.go'"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRun(t *testing.T) {
+	r := &Runner{
+		Checks: []config.Check{
+			{Name: "pass", Run: "echo ok"},
+			{Name: "fail", Run: "echo broken >&2; exit 1"},
+			{Name: "skipped", Run: "exit 1", Paths: []string{"*.py"}},
+			{Name: "format", Run: "echo {files}", Formatter: true},
+		},
+		Files: []string{"main.go"},
+	}
+	var mu sync.Mutex
+	started := []string{}
+	results := r.Run(context.Background(), func(i int, result Result) {
+		if result.Status == Running {
+			mu.Lock()
+			started = append(started, result.Check.Name)
+			mu.Unlock()
+		}
+	})
+
+	want := []struct {
+		status Status
+		output string
+	}{
+		{Passed, "ok"},
+		{Failed, "broken"},
+		{Skipped, ""},
+		{Passed, "main.go"},
+	}
+	for i, w := range want {
+		if results[i].Status != w.status {
+			t.Errorf("%s: got status %d, want %d", results[i].Check.Name, results[i].Status, w.status)
+		}
+		if got := strings.TrimSpace(results[i].Output); got != w.output {
+			t.Errorf("%s: got output %q, want %q", results[i].Check.Name, got, w.output)
+		}
+	}
+	if len(started) == 0 || started[0] != "format" {
+		t.Errorf("formatters should run first, got %v", started)
+	}
+	if failures := Failures(results); len(failures) != 1 || failures[0].Check.Name != "fail" {
+		t.Errorf("got failures %v", failures)
+	}
+}
+
+func TestRunWithLog(t *testing.T) {
+	r := &Runner{
+		Checks: []config.Check{
+			{Name: "fail", Run: "exit 1"},
+			{Name: "skipped", Run: "exit 1", Paths: []string{"*.py"}},
+		},
+		Files: []string{"main.go"},
+	}
+	var out strings.Builder
+	RunWithLog(r, &out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "Running checks" {
+		t.Errorf("expected a heading, got %q", lines[0])
+	}
+	for _, want := range []string{"Started fail", "Failed fail after ", "Skipped skipped, no matching files"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in the log, got %q", want, out.String())
+		}
+	}
+}
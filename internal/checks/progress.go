@@ -0,0 +1,179 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrAborted is returned when the user stops the checks
+var ErrAborted = errors.New("checks aborted")
+
+// Styles colour the progress of the checks
+type Styles struct {
+	Passed  lipgloss.Style
+	Failed  lipgloss.Style
+	Muted   lipgloss.Style
+	Spinner lipgloss.Style
+}
+
+// DefaultStyles returns the styles used when the runner has none, which use
+// the colours of the terminal's palette
+func DefaultStyles() *Styles {
+	return &Styles{
+		Passed:  lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+		Failed:  lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		Muted:   lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Spinner: lipgloss.NewStyle().Foreground(lipgloss.Color("5")),
+	}
+}
+
+type updateMsg struct {
+	i      int
+	result Result
+}
+
+type doneMsg struct{}
+
+// progress shows each check with its status while they run
+type progress struct {
+	runner  *Runner
+	styles  *Styles
+	results []Result
+	spinner spinner.Model
+	cancel  context.CancelFunc
+	aborted bool
+	done    bool
+}
+
+func (m progress) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m progress) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case updateMsg:
+		m.results[msg.i] = msg.result
+	case doneMsg:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.aborted = true
+			m.cancel()
+			return m, tea.Quit
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m progress) View() string {
+	var b strings.Builder
+	b.WriteString(m.runner.text("checks.running") + "\n\n")
+	for _, r := range m.results {
+		b.WriteString(m.line(r) + "\n")
+	}
+	if !m.done {
+		b.WriteString("\n" + m.styles.Muted.Render(m.runner.text("checks.stop")) + "\n")
+	}
+	return b.String()
+}
+
+func (m progress) line(r Result) string {
+	name := r.Check.Label()
+	switch r.Status {
+	case Running:
+		return m.spinner.View() + " " + name
+	case Passed:
+		return m.styles.Passed.Render("✓") + " " + name + " " + m.styles.Muted.Render(formatDuration(r.Duration))
+	case Failed:
+		return m.styles.Failed.Render("✗") + " " + name + " " + m.styles.Muted.Render(formatDuration(r.Duration))
+	case Skipped:
+		return m.styles.Muted.Render("- " + m.runner.text("checks.noFiles", name))
+	default:
+		return m.styles.Muted.Render("· " + name)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// RunWithProgress runs the checks while showing their progress
+func RunWithProgress(r *Runner) ([]Result, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := spinner.New()
+	s.Spinner = spinner.MiniDot
+	styles := r.Styles
+	if styles == nil {
+		styles = DefaultStyles()
+	}
+	s.Style = styles.Spinner
+	m := progress{runner: r, styles: styles, results: make([]Result, len(r.Checks)), spinner: s, cancel: cancel}
+	for i, c := range r.Checks {
+		m.results[i] = Result{Check: c, Status: Pending}
+	}
+
+	p := tea.NewProgram(m)
+	results := make(chan []Result, 1)
+	go func() {
+		results <- r.Run(ctx, func(i int, result Result) {
+			p.Send(updateMsg{i: i, result: result})
+		})
+		p.Send(doneMsg{})
+	}()
+
+	final, err := p.Run()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if final.(progress).aborted {
+		<-results
+		return nil, ErrAborted
+	}
+	return <-results, nil
+}
+
+// RunWithLog runs the checks, writing a line whenever one starts or finishes
+// rather than redrawing their progress
+func RunWithLog(r *Runner, w io.Writer) []Result {
+	_, _ = fmt.Fprintln(w, r.text("checks.running"))
+	var mu sync.Mutex
+	return r.Run(context.Background(), func(i int, result Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintln(w, r.logLine(result))
+	})
+}
+
+// logLine describes a check's status in words
+func (r *Runner) logLine(result Result) string {
+	name := result.Check.Label()
+	switch result.Status {
+	case Running:
+		return r.text("checks.started", name)
+	case Passed:
+		return r.text("checks.passed", name, formatDuration(result.Duration))
+	case Failed:
+		return r.text("checks.failedAfter", name, formatDuration(result.Duration))
+	case Skipped:
+		return r.text("checks.skipped", name)
+	default:
+		return r.text("checks.waiting", name)
+	}
+}
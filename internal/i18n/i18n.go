@@ -0,0 +1,137 @@
+// Package i18n holds the text of meteor's prompts in each supported language.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is used when no supported locale is configured
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Catalogue is the text of every prompt in one locale
+type Catalogue struct {
+	locale string
+	text   map[string]string
+}
+
+// Load returns the catalogue for a locale such as "de" or "fr_FR.UTF-8", with
+// the overrides replacing individual strings. Text missing from a translation
+// falls back to English
+func Load(locale string, overrides map[string]string) (*Catalogue, error) {
+	english, err := readLocale(DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalogue{locale: DefaultLocale, text: english}
+
+	if name := supported(locale); name != "" {
+		translated, err := readLocale(name)
+		if err != nil {
+			return nil, err
+		}
+		for id, text := range translated {
+			c.text[id] = text
+		}
+		c.locale = name
+	} else if locale != "" {
+		return nil, fmt.Errorf("unknown locale %q, the supported locales are %s", locale, strings.Join(Locales(), ", "))
+	}
+
+	for id, text := range overrides {
+		if _, ok := english[id]; !ok {
+			return nil, fmt.Errorf("unknown string %q", id)
+		}
+		c.text[id] = text
+	}
+	return c, nil
+}
+
+// English returns the catalogue without any overrides
+func English() *Catalogue {
+	c, err := Load(DefaultLocale, nil)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// T returns the text with the given id, formatted with the args if there
+// are any
+func (c *Catalogue) T(id string, args ...any) string {
+	text, ok := c.text[id]
+	if !ok {
+		text = id
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Locale returns the name of the catalogue's locale
+func (c *Catalogue) Locale() string {
+	return c.locale
+}
+
+// Locales returns the names of the supported locales
+func Locales() []string {
+	entries, _ := locales.ReadDir("locales")
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = strings.TrimSuffix(e.Name(), path.Ext(e.Name()))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EnvironmentLocale returns the supported locale picked by LC_ALL,
+// LC_MESSAGES or LANG, or "" when none of them names one
+func EnvironmentLocale() string {
+	for _, v := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(v); value != "" {
+			return supported(value)
+		}
+	}
+	return ""
+}
+
+// supported returns the name of the locale file for a locale such as
+// "pt_BR.UTF-8", trying the language and region before the language alone
+func supported(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	locale = strings.ToLower(strings.ReplaceAll(locale, "-", "_"))
+	language, _, _ := strings.Cut(locale, "_")
+	for _, name := range Locales() {
+		if name == locale {
+			return name
+		}
+	}
+	for _, name := range Locales() {
+		if name == language {
+			return name
+		}
+	}
+	return ""
+}
+
+func readLocale(name string) (map[string]string, error) {
+	data, err := locales.ReadFile("locales/" + name + ".json")
+	if err != nil {
+		return nil, err
+	}
+	text := map[string]string{}
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil, fmt.Errorf("could not read the %s strings: %w", name, err)
+	}
+	return text, nil
+}
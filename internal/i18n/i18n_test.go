@@ -0,0 +1,87 @@
+package i18n
+
+import "testing"
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		locale    string
+		overrides map[string]string
+		id        string
+		want      string
+	}{
+		{"english", "", nil, "confirm.title", "Ready to commit?"},
+		{"language", "de", nil, "confirm.title", "Bereit zum Committen?"},
+		{"environment style", "fr_FR.UTF-8", nil, "confirm.title", "Prêt à committer ?"},
+		{"override", "es", map[string]string{"confirm.title": "¿Enviar?"}, "confirm.title", "¿Enviar?"},
+		{"unknown id", "", nil, "nope", "nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(tt.locale, tt.overrides)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.T(tt.id); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := Load("tlh", nil); err == nil {
+		t.Error("expected an unsupported locale to be rejected")
+	}
+	if _, err := Load("", map[string]string{"confirm.titel": "Ship it?"}); err == nil {
+		t.Error("expected an override of an unknown string to be rejected")
+	}
+}
+
+func TestTranslationsAreComplete(t *testing.T) {
+	english, err := readLocale(DefaultLocale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, locale := range Locales() {
+		translated, err := readLocale(locale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id := range english {
+			if _, ok := translated[id]; !ok {
+				t.Errorf("%s is missing %q", locale, id)
+			}
+		}
+		for id := range translated {
+			if _, ok := english[id]; !ok {
+				t.Errorf("%s has %q, which is not used", locale, id)
+			}
+		}
+	}
+}
+
+func TestEnvironmentLocale(t *testing.T) {
+	tests := []struct {
+		lcAll, lang string
+		want        string
+	}{
+		{"", "de_DE.UTF-8", "de"},
+		{"C", "de_DE.UTF-8", ""},
+		{"es_ES", "de_DE.UTF-8", "es"},
+		{"", "pt_BR.UTF-8", ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.lang)
+		if got := EnvironmentLocale(); got != tt.want {
+			t.Errorf("LC_ALL=%q LANG=%q: expected %q, got %q", tt.lcAll, tt.lang, tt.want, got)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	c := English()
+	if got := c.T("message.accessibleTitle", "feat: "); got != `Message, following "feat: "` {
+		t.Errorf("got %q", got)
+	}
+}
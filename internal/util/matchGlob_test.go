@@ -0,0 +1,35 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stefanlogue/meteor/internal/util"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob string
+		name string
+		want bool
+	}{
+		{"*.env", ".env", true},
+		{"*.env", "config/prod.env", true},
+		{"*.env", "env.go", false},
+		{"dist/**", "dist/app.js", true},
+		{"dist/**", "dist/js/app.js", true},
+		{"dist/**", "src/dist/app.js", false},
+		{"**/dist/**", "src/dist/app.js", true},
+		{"assets/*.png", "assets/logo.png", true},
+		{"assets/*.png", "assets/icons/logo.png", false},
+		{"assets/**/*.png", "assets/logo.png", true},
+		{"assets/**/*.png", "assets/icons/logo.png", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.glob+" "+tt.name, func(t *testing.T) {
+			if got := util.MatchGlob(tt.glob, tt.name); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.glob, tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
-package main
+package util
 
-func wordWrap(input string, width int) string {
+// WordWrap wraps the input at the given width, splitting only on whitespace
+func WordWrap(input string, width int) string {
 	words := splitIntoWords(input)
 	if len(words) == 0 {
 		return ""
@@ -1,4 +1,4 @@
-package main
+package util
 
 import "testing"
 
@@ -36,7 +36,7 @@ func TestWordWrap(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.desc, func(t *testing.T) {
-			got := wordWrap(tc.input, tc.width)
+			got := WordWrap(tc.input, tc.width)
 			if got != tc.expected {
 				t.Errorf("expected %q, got %q", tc.expected, got)
 			}
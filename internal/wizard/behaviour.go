@@ -0,0 +1,249 @@
+package wizard
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/lint"
+)
+
+// scopeStep asks for the scope, starting from the type's default scope, and
+// then applies the settings of the chosen type and scope
+func scopeStep() *Step {
+	// scopes are the ones ticked when several can be picked from a list
+	var scopes []string
+	return &Step{
+		Name: "scope",
+		Pre: []Hook{defaultScope, func(s *State) error {
+			scopes = commit.SplitScopes(s.Config.Commit(), s.Commit.Scope)
+			return nil
+		}},
+		Post: []Hook{func(s *State) error {
+			if picksScopes(s) {
+				s.Commit.Scope = commit.JoinScopes(s.Config.Commit(), scopes)
+				return nil
+			}
+			if len(scopeOptions(s)) == 0 {
+				return scopeError(s.Config, s.Commit.Scope)
+			}
+			return nil
+		}, applyBehaviour},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					scopeField(s, &scopes),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.Scope != "" {
+				s.Commit.Scope = a.Scope
+				scopes = commit.SplitScopes(s.Config.Commit(), a.Scope)
+			}
+		},
+	}
+}
+
+// defaultScope picks the type's default scope if the commit has none
+func defaultScope(s *State) error {
+	if s.Commit.Scope == "" {
+		s.Commit.Scope = s.Config.Behaviours.Type(s.Commit.Type).DefaultScope
+	}
+	return nil
+}
+
+// applyBehaviour applies the settings of the chosen type and scope that don't
+// need to be asked about
+func applyBehaviour(s *State) error {
+	b := s.Behaviour()
+	if b.SkipBreakingChange {
+		s.Commit.IsBreakingChange = false
+	}
+	if strings.TrimSpace(s.Commit.Body) == "" && b.BodyTemplate != "" {
+		s.Commit.Body = b.BodyTemplate
+	}
+	for _, t := range b.Trailers {
+		if t.Value != "" && !hasTrailer(s.Commit, t.Key) {
+			s.Commit.Trailers = append(s.Commit.Trailers, commit.Trailer{Key: t.Key, Value: t.Value})
+		}
+	}
+	return nil
+}
+
+// breakingStep asks whether the commit is a breaking change, unless the config
+// or the chosen type or scope rules it out
+func breakingStep() *Step {
+	return &Step{
+		Name: "breaking",
+		Skip: func(s *State) bool {
+			return s.Config.SkipBreakingChange || s.Behaviour().SkipBreakingChange
+		},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(s.Config.text("breaking.title")).
+						Description(s.Config.text("breaking.description")).
+						Affirmative(s.Config.text("breaking.yes")).
+						Negative(s.Config.text("breaking.no")).
+						Value(&s.Commit.IsBreakingChange),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.IsBreakingChange {
+				s.Commit.IsBreakingChange = true
+			}
+		},
+	}
+}
+
+// requiredTicketStep asks for a ticket number when the chosen type or scope
+// needs one and none was given
+func requiredTicketStep() *Step {
+	return &Step{
+		Name: "required-ticket",
+		Skip: func(s *State) bool {
+			return !s.Behaviour().RequireTicket || s.HasTicket() && ticketGiven(s.Commit.TicketNumber)
+		},
+		Post: []Hook{checkTicket},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Title(s.Config.text("ticket.title")).
+						Description(s.Config.text("ticket.required")).
+						CharLimit(24).
+						Validate(func(value string) error {
+							if !ticketGiven(value) {
+								return errors.New(s.Config.text("ticket.empty"))
+							}
+							return nil
+						}).
+						Value(&s.Commit.TicketNumber),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.TicketNumber != "" {
+				s.Commit.TicketNumber = a.TicketNumber
+			}
+		},
+	}
+}
+
+// ticketGiven reports whether a ticket number is more than the board's prefix
+func ticketGiven(ticket string) bool {
+	ticket = strings.TrimSpace(ticket)
+	return ticket != "" && !strings.HasSuffix(ticket, "-")
+}
+
+// checkTicket fails if the required ticket is missing, and otherwise sets the
+// board from the ticket if none was chosen
+func checkTicket(s *State) error {
+	if !ticketGiven(s.Commit.TicketNumber) {
+		return errors.New(s.Config.text("behaviour.ticket"))
+	}
+	if !s.HasTicket() {
+		s.Commit.Board, _, _ = strings.Cut(s.Commit.TicketNumber, "-")
+	}
+	return nil
+}
+
+// trailersStep asks for the trailers the chosen type or scope needs that have
+// no value in the config
+func trailersStep() *Step {
+	var asked []commit.Trailer
+	return &Step{
+		Name: "trailers",
+		Skip: func(s *State) bool {
+			return len(missingTrailers(s)) == 0
+		},
+		Pre: []Hook{func(s *State) error {
+			asked = missingTrailers(s)
+			return nil
+		}},
+		Form: func(s *State) *huh.Form {
+			fields := []huh.Field{}
+			for i := range asked {
+				fields = append(fields, huh.NewInput().
+					Title(asked[i].Key).
+					Description(s.Config.text("trailer.description")).
+					Validate(func(value string) error {
+						if strings.TrimSpace(value) == "" {
+							return errors.New(s.Config.text("trailer.empty"))
+						}
+						return nil
+					}).
+					Value(&asked[i].Value))
+			}
+			return huh.NewForm(
+				huh.NewGroup(fields...),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			for i := range asked {
+				if v, ok := a.Values[asked[i].Key]; ok {
+					asked[i].Value = v
+				}
+			}
+		},
+		Post: []Hook{func(s *State) error {
+			for _, t := range asked {
+				if strings.TrimSpace(t.Value) == "" {
+					return errors.New(s.Config.text("behaviour.trailer", t.Key))
+				}
+				s.Commit.Trailers = append(s.Commit.Trailers, t)
+			}
+			return nil
+		}},
+	}
+}
+
+// missingTrailers returns the trailers the commit needs without a value in
+// the config or in the commit already
+func missingTrailers(s *State) []commit.Trailer {
+	missing := []commit.Trailer{}
+	for _, t := range s.Behaviour().Trailers {
+		if t.Value == "" && !hasTrailer(s.Commit, t.Key) {
+			missing = append(missing, commit.Trailer{Key: t.Key})
+		}
+	}
+	return missing
+}
+
+func hasTrailer(c commit.Commit, key string) bool {
+	for _, t := range c.Trailers {
+		if strings.EqualFold(t.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageConfig returns the config for the message form, which refuses an
+// empty body when the chosen type or scope needs one
+func messageConfig(s *State) Config {
+	c := s.Config
+	if !s.Behaviour().RequireBody {
+		return c
+	}
+	c.Rules = lint.Rules{}
+	for name, rule := range s.Config.Rules {
+		c.Rules[name] = rule
+	}
+	c.Rules["body-empty"] = lint.Rule{Severity: lint.Error, When: lint.Never}
+	return c
+}
+
+// checkBody fails if the body is empty when the chosen type or scope needs
+// one
+func checkBody(s *State) error {
+	if s.Behaviour().RequireBody && strings.TrimSpace(s.Commit.Body) == "" {
+		return errors.New(s.Config.text("behaviour.body"))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package wizard
+
+import "strings"
+
+// diffPane returns a pane showing the staged diff, or nil if there is no way
+// to read it
+func diffPane(s *State) []Pane {
+	if s.Config.StagedDiff == nil {
+		return nil
+	}
+	diff, err := s.Config.StagedDiff()
+	if err != nil {
+		diff = err.Error()
+	}
+	highlighted := highlightDiff(s.Config.styles(), diff)
+	return []Pane{
+		{
+			Title:      s.Config.text("diff.title"),
+			Content:    func() string { return highlighted },
+			Toggle:     s.Config.keys().ToggleDiff,
+			Scrollable: true,
+		},
+	}
+}
+
+// highlightDiff colours the lines of a unified diff
+func highlightDiff(st *Styles, diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git"),
+			strings.HasPrefix(line, "index "),
+			strings.HasPrefix(line, "+++"),
+			strings.HasPrefix(line, "---"):
+			lines[i] = st.DiffHeader.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = st.DiffHunk.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = st.DiffAdded.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = st.DiffRemoved.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package wizard
+
+import "github.com/charmbracelet/huh"
+
+// Driver collects the answers for a single step
+type Driver interface {
+	Drive(step *Step, s *State) error
+}
+
+// Interactive drives the wizard with huh forms in the terminal
+type Interactive struct {
+	Theme *huh.Theme
+}
+
+func (d Interactive) Drive(step *Step, s *State) error {
+	if step.Form == nil {
+		return nil
+	}
+	keys := s.Config.keys()
+	form := step.Form(s).WithKeyMap(&keys.KeyMap)
+	if d.Theme != nil {
+		form = form.WithTheme(d.Theme)
+	}
+	// the panes need the screen to be redrawn, so accessible forms run on
+	// their own
+	if s.Config.Accessible {
+		return form.WithAccessible(true).Run()
+	}
+	panes := []Pane{}
+	if !step.HidePreview {
+		panes = append(panes, previewPane(s))
+	}
+	if step.Panes != nil {
+		panes = append(panes, step.Panes(s)...)
+	}
+	bindings := []Binding{}
+	if step.Bindings != nil {
+		bindings = step.Bindings(s)
+	}
+	if len(panes) == 0 && len(bindings) == 0 {
+		return form.Run()
+	}
+	return runLayout(form, panes, bindings, s.Config)
+}
+
+// Answers are pre-recorded responses used to drive the wizard headlessly
+type Answers struct {
+	Board            string   `json:"board"`
+	TicketNumber     string   `json:"ticketNumber"`
+	Type             string   `json:"type"`
+	Scope            string   `json:"scope"`
+	IsBreakingChange bool     `json:"isBreakingChange"`
+	Coauthors        []string `json:"coauthors"`
+	// Message is typed after the rendered message template
+	Message string            `json:"message"`
+	Body    string            `json:"body"`
+	Abort   bool              `json:"abort"`
+	Values  map[string]string `json:"values"`
+}
+
+// Headless drives the wizard without a terminal by feeding it answers
+type Headless struct {
+	Answers Answers
+}
+
+func (d Headless) Drive(step *Step, s *State) error {
+	if step.Answer != nil {
+		step.Answer(s, d.Answers)
+	}
+	return nil
+}
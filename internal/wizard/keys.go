@@ -0,0 +1,245 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/internal/i18n"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+// KeyMap is the form key map along with the bindings for any panes shown
+// beside the form
+type KeyMap struct {
+	huh.KeyMap
+	ToggleDiff   key.Binding
+	ToggleLayout key.Binding
+	ScrollUp     key.Binding
+	ScrollDown   key.Binding
+	// PreviousMessage and NextMessage cycle through the messages written
+	// before while the message form is shown
+	PreviousMessage key.Binding
+	NextMessage     key.Binding
+}
+
+// DefaultKeyMap returns the key bindings used when the config doesn't change
+// them
+func DefaultKeyMap() *KeyMap {
+	keys := &KeyMap{
+		KeyMap: *huh.NewDefaultKeyMap(),
+		// the text area already uses ctrl+d, ctrl+e, ctrl+k, ctrl+t and ctrl+u
+		ToggleDiff:   key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "staged diff")),
+		ToggleLayout: key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "move panes")),
+		ScrollUp:     key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "scroll up")),
+		ScrollDown:   key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdown", "scroll down")),
+		// up and down already pick between the suggestions
+		PreviousMessage: key.NewBinding(key.WithKeys("alt+up"), key.WithHelp("alt+up", "previous message")),
+		NextMessage:     key.NewBinding(key.WithKeys("alt+down"), key.WithHelp("alt+down", "next message")),
+	}
+	keys.Quit = key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit"))
+	// tab is left out so that it can't be pressed by mistake in the body
+	keys.Text.Next = key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next"))
+	keys.Input.Next = key.NewBinding(key.WithKeys("enter", "tab"), key.WithHelp("enter / tab", "next"))
+	keys.Confirm.Toggle = key.NewBinding(key.WithKeys("left", "right", "h", "l"), key.WithHelp("left / right", "toggle"))
+	keys.Confirm.Submit = key.NewBinding(key.WithKeys("enter", "tab"), key.WithHelp("enter / tab", "submit"))
+	return keys
+}
+
+// keyPresets are the bindings each preset applies before the config's own
+var keyPresets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"up":         {"k", "up", "ctrl+p"},
+		"down":       {"j", "down", "ctrl+n"},
+		"scrollUp":   {"alt+k", "pgup"},
+		"scrollDown": {"alt+j", "pgdown"},
+	},
+}
+
+// fieldKinds name the kinds of field whose bindings must not conflict
+var fieldKinds = []string{"input", "text", "select", "multi-select", "note", "confirm"}
+
+// textAreaKeys are used for editing by the inputs and the text area, so no
+// action may take them there. Each is mapped to the action allowed to use it,
+// if any
+var textAreaKeys = map[string]string{
+	"ctrl+d": "",
+	"ctrl+e": "editor",
+	"ctrl+k": "",
+	"ctrl+t": "",
+	"ctrl+u": "",
+}
+
+// keyAction is an action that can be remapped in the config
+type keyAction struct {
+	name string
+	// bindings returns the action's binding for each kind of field, or under
+	// "" when it applies whatever field is focused
+	bindings func(k *KeyMap) map[string]*key.Binding
+}
+
+var keyActions = []keyAction{
+	{"next", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{
+			"input": &k.Input.Next, "text": &k.Text.Next, "select": &k.Select.Next,
+			"multi-select": &k.MultiSelect.Next, "note": &k.Note.Next, "confirm": &k.Confirm.Next,
+		}
+	}},
+	{"prev", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{
+			"input": &k.Input.Prev, "text": &k.Text.Prev, "select": &k.Select.Prev,
+			"multi-select": &k.MultiSelect.Prev, "note": &k.Note.Prev, "confirm": &k.Confirm.Prev,
+		}
+	}},
+	{"submit", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{
+			"input": &k.Input.Submit, "text": &k.Text.Submit, "select": &k.Select.Submit,
+			"multi-select": &k.MultiSelect.Submit, "note": &k.Note.Submit, "confirm": &k.Confirm.Submit,
+		}
+	}},
+	{"newLine", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"text": &k.Text.NewLine}
+	}},
+	{"editor", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"text": &k.Text.Editor}
+	}},
+	{"previousMessage", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"input": &k.PreviousMessage, "text": &k.PreviousMessage}
+	}},
+	{"nextMessage", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"input": &k.NextMessage, "text": &k.NextMessage}
+	}},
+	{"toggle", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"confirm": &k.Confirm.Toggle}
+	}},
+	{"select", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"multi-select": &k.MultiSelect.Toggle}
+	}},
+	{"up", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"select": &k.Select.Up, "multi-select": &k.MultiSelect.Up}
+	}},
+	{"down", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"select": &k.Select.Down, "multi-select": &k.MultiSelect.Down}
+	}},
+	{"filter", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"select": &k.Select.Filter, "multi-select": &k.MultiSelect.Filter}
+	}},
+	{"quit", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"": &k.Quit}
+	}},
+	{"toggleDiff", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"": &k.ToggleDiff}
+	}},
+	{"toggleLayout", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"": &k.ToggleLayout}
+	}},
+	{"scrollUp", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"": &k.ScrollUp}
+	}},
+	{"scrollDown", func(k *KeyMap) map[string]*key.Binding {
+		return map[string]*key.Binding{"": &k.ScrollDown}
+	}},
+}
+
+// NewKeyMap returns the default bindings changed by the preset and then by
+// the actions set in the config. It fails if a key would do two things in
+// the same field
+func NewKeyMap(c config.Keys) (*KeyMap, error) {
+	preset := c.Preset
+	if preset == "" {
+		preset = "default"
+	}
+	presetKeys, ok := keyPresets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q", c.Preset)
+	}
+
+	keys := DefaultKeyMap()
+	configured := c.Bindings()
+	for _, action := range keyActions {
+		bound, ok := configured[action.name]
+		if !ok {
+			bound, ok = presetKeys[action.name]
+		}
+		if !ok {
+			continue
+		}
+		if len(bound) == 0 {
+			return nil, fmt.Errorf("no keys given for %s", action.name)
+		}
+		for _, b := range action.bindings(keys) {
+			*b = key.NewBinding(key.WithKeys(bound...), key.WithHelp(strings.Join(bound, " / "), b.Help().Desc))
+		}
+	}
+
+	if err := keys.validate(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// validate checks that no key is bound to two actions in the same kind of
+// field, or takes a key the inputs and text area edit with. Next and submit
+// may share keys as only one of them is enabled at a time
+func (k *KeyMap) validate() error {
+	for _, kind := range fieldKinds {
+		bound := map[string]string{}
+		for _, action := range keyActions {
+			bindings := action.bindings(k)
+			b, ok := bindings[kind]
+			if !ok {
+				b, ok = bindings[""]
+			}
+			if !ok {
+				continue
+			}
+			for _, pressed := range b.Keys() {
+				if allowed, reserved := textAreaKeys[pressed]; reserved && allowed != action.name && (kind == "input" || kind == "text") {
+					return fmt.Errorf("key %q is used for editing in %s fields and can't be bound to %s", pressed, kind, action.name)
+				}
+				other, taken := bound[pressed]
+				if taken && !(other == "next" && action.name == "submit") {
+					return fmt.Errorf("key %q is bound to both %s and %s in %s fields", pressed, other, action.name, kind)
+				}
+				bound[pressed] = action.name
+			}
+		}
+	}
+	return nil
+}
+
+// Translate returns a copy of the key map with the help of the bindings set
+// by meteor taken from the catalogue, or in English if it is nil
+func (k *KeyMap) Translate(c *i18n.Catalogue) *KeyMap {
+	if c == nil {
+		c = i18n.English()
+	}
+	translated := *k
+	for id, b := range map[string]*key.Binding{
+		"keys.toggleDiff":      &translated.ToggleDiff,
+		"keys.toggleLayout":    &translated.ToggleLayout,
+		"keys.scrollUp":        &translated.ScrollUp,
+		"keys.scrollDown":      &translated.ScrollDown,
+		"keys.previousMessage": &translated.PreviousMessage,
+		"keys.nextMessage":     &translated.NextMessage,
+		"keys.quit":            &translated.Quit,
+		"keys.next":            &translated.Text.Next,
+		"keys.inputNext":       &translated.Input.Next,
+		"keys.toggle":          &translated.Confirm.Toggle,
+		"keys.submit":          &translated.Confirm.Submit,
+	} {
+		b.SetHelp(b.Help().Key, c.T(id))
+	}
+	return &translated
+}
+
+// keys returns the key bindings to use for the wizard's forms
+func (c Config) keys() *KeyMap {
+	if c.KeyMap == nil {
+		return DefaultKeyMap().Translate(c.Catalogue)
+	}
+	return c.KeyMap.Translate(c.Catalogue)
+}
@@ -0,0 +1,107 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stefanlogue/meteor/internal/i18n"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    config.Keys
+		check   func(k *KeyMap) []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "defaults",
+			keys:  config.Keys{},
+			check: func(k *KeyMap) []string { return k.Text.Next.Keys() },
+			want:  []string{"enter"},
+		},
+		{
+			name:  "vim preset",
+			keys:  config.Keys{Preset: "vim"},
+			check: func(k *KeyMap) []string { return k.ScrollDown.Keys() },
+			want:  []string{"alt+j", "pgdown"},
+		},
+		{
+			name:  "config overrides the preset",
+			keys:  config.Keys{Preset: "vim", Down: config.KeyList{"j"}},
+			check: func(k *KeyMap) []string { return k.MultiSelect.Down.Keys() },
+			want:  []string{"j"},
+		},
+		{
+			name:  "action applies to every field",
+			keys:  config.Keys{Prev: config.KeyList{"esc"}},
+			check: func(k *KeyMap) []string { return append(k.Input.Prev.Keys(), k.Confirm.Prev.Keys()...) },
+			want:  []string{"esc", "esc"},
+		},
+		{
+			name:  "next and submit may share keys",
+			keys:  config.Keys{Next: config.KeyList{"ctrl+s"}, Submit: config.KeyList{"ctrl+s"}},
+			check: func(k *KeyMap) []string { return k.Text.Submit.Keys() },
+			want:  []string{"ctrl+s"},
+		},
+		{
+			name:    "unknown preset",
+			keys:    config.Keys{Preset: "emacs"},
+			wantErr: `unknown key preset "emacs"`,
+		},
+		{
+			name:    "no keys",
+			keys:    config.Keys{Editor: config.KeyList{}},
+			wantErr: "no keys given for editor",
+		},
+		{
+			name:    "conflict in one field",
+			keys:    config.Keys{Editor: config.KeyList{"alt+enter"}},
+			wantErr: `key "alt+enter" is bound to both newLine and editor in text fields`,
+		},
+		{
+			name:    "key used by the text area",
+			keys:    config.Keys{ScrollDown: config.KeyList{"ctrl+d"}},
+			wantErr: `key "ctrl+d" is used for editing in input fields and can't be bound to scrollDown`,
+		},
+		{
+			name:    "conflict with a pane binding",
+			keys:    config.Keys{ToggleDiff: config.KeyList{"j"}},
+			wantErr: `key "j" is bound to both down and toggleDiff in select fields`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKeyMap(tt.keys)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(tt.check(k), ","); got != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTranslateKeyMap(t *testing.T) {
+	catalogue, err := i18n.Load("de", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := DefaultKeyMap()
+	translated := keys.Translate(catalogue)
+	if got := translated.ToggleDiff.Help().Desc; got != "gestagte Änderungen" {
+		t.Errorf("expected the translated help, got %q", got)
+	}
+	if got := keys.ToggleDiff.Help().Desc; got != "staged diff" {
+		t.Errorf("expected the key map to be left alone, got %q", got)
+	}
+}
@@ -0,0 +1,266 @@
+package wizard
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sideBySideMinWidth is the narrowest terminal that panes are shown beside
+// the form in, rather than below it
+const sideBySideMinWidth = 120
+
+// Pane is extra content shown beside or below a step's form
+type Pane struct {
+	Title string
+	// Content is called on every render so that the pane can follow the form
+	Content func() string
+	// Toggle shows and hides the pane, which then starts hidden. Panes
+	// without a toggle are always shown
+	Toggle key.Binding
+	// Scrollable panes can be scrolled with the scroll keys
+	Scrollable bool
+}
+
+// Binding runs an action when its key is pressed while a step's form is shown
+type Binding struct {
+	Key    key.Binding
+	Action func()
+}
+
+func (p Pane) hasToggle() bool {
+	return len(p.Toggle.Keys()) > 0
+}
+
+// layout runs a form with its panes in a single program
+type layout struct {
+	form      *huh.Form
+	panes     []Pane
+	bindings  []Binding
+	visible   []bool
+	viewports []viewport.Model
+	keys      *KeyMap
+	config    Config
+	styles    *Styles
+	width     int
+	height    int
+	bottom    bool
+}
+
+func newLayout(form *huh.Form, panes []Pane, bindings []Binding, c Config) *layout {
+	l := &layout{
+		form:      form,
+		panes:     panes,
+		bindings:  bindings,
+		visible:   make([]bool, len(panes)),
+		viewports: make([]viewport.Model, len(panes)),
+		keys:      c.keys(),
+		config:    c,
+		styles:    c.styles(),
+	}
+	for i, p := range panes {
+		l.visible[i] = !p.hasToggle()
+		l.viewports[i] = viewport.New(0, 0)
+	}
+	return l
+}
+
+// runLayout runs the form with the panes and returns huh.ErrUserAborted if
+// the user quits
+func runLayout(form *huh.Form, panes []Pane, bindings []Binding, c Config) error {
+	m, err := tea.NewProgram(newLayout(form, panes, bindings, c)).Run()
+	if err != nil {
+		return err
+	}
+	if m.(*layout).form.State == huh.StateAborted {
+		return huh.ErrUserAborted
+	}
+	return nil
+}
+
+func (l *layout) Init() tea.Cmd {
+	return l.form.Init()
+}
+
+func (l *layout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		l.width, l.height = msg.Width, msg.Height
+		l.bottom = l.width < sideBySideMinWidth
+		return l.updateForm(l.formSize())
+	case tea.KeyMsg:
+		for _, b := range l.bindings {
+			if key.Matches(msg, b.Key) {
+				b.Action()
+				return l, nil
+			}
+		}
+		for i, p := range l.panes {
+			if p.hasToggle() && key.Matches(msg, p.Toggle) {
+				l.visible[i] = !l.visible[i]
+				return l.updateForm(l.formSize())
+			}
+		}
+		// the keys are left to the form while there is nothing for them to
+		// move
+		switch {
+		case l.anyVisible() && key.Matches(msg, l.keys.ToggleLayout):
+			l.bottom = !l.bottom
+			return l.updateForm(l.formSize())
+		case l.anyScrollable() && key.Matches(msg, l.keys.ScrollUp):
+			l.scroll(-1)
+			return l, nil
+		case l.anyScrollable() && key.Matches(msg, l.keys.ScrollDown):
+			l.scroll(1)
+			return l, nil
+		}
+	}
+	return l.updateForm(msg)
+}
+
+// updateForm passes the message to the form and quits once it is done
+func (l *layout) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg == nil {
+		return l, nil
+	}
+	m, cmd := l.form.Update(msg)
+	l.form = m.(*huh.Form)
+	if l.form.State != huh.StateNormal {
+		return l, tea.Quit
+	}
+	return l, cmd
+}
+
+// formSize returns the size the form should take up with the visible panes
+func (l *layout) formSize() tea.Msg {
+	if l.width == 0 {
+		return nil
+	}
+	if l.bottom || !l.anyVisible() {
+		return tea.WindowSizeMsg{Width: l.width, Height: l.height}
+	}
+	return tea.WindowSizeMsg{Width: l.width / 2, Height: l.height}
+}
+
+// scroll moves every visible scrollable pane by half a page
+func (l *layout) scroll(direction int) {
+	for i, p := range l.panes {
+		if !l.visible[i] || !p.Scrollable {
+			continue
+		}
+		if direction < 0 {
+			l.viewports[i].HalfViewUp()
+		} else {
+			l.viewports[i].HalfViewDown()
+		}
+	}
+}
+
+// anyScrollable reports whether a scrollable pane is shown
+func (l *layout) anyScrollable() bool {
+	for i, p := range l.panes {
+		if l.visible[i] && p.Scrollable {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *layout) anyVisible() bool {
+	for _, v := range l.visible {
+		if v {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *layout) View() string {
+	if l.form.State != huh.StateNormal {
+		return ""
+	}
+
+	form := l.form.View() + "\n" + l.help()
+	if !l.anyVisible() || l.width == 0 {
+		return form
+	}
+
+	paneWidth := l.width - lipgloss.Width(form) - 1
+	paneHeight := l.height / 2
+	if l.bottom {
+		paneWidth = l.width
+		paneHeight = l.height - lipgloss.Height(form) - 1
+	}
+	paneHeight = max(paneHeight, 5)
+
+	visible := []Pane{}
+	for i, p := range l.panes {
+		if l.visible[i] {
+			visible = append(visible, p)
+		}
+	}
+	views := []string{}
+	for i, p := range l.panes {
+		if !l.visible[i] {
+			continue
+		}
+		views = append(views, l.paneView(i, p, paneWidth, paneHeight/len(visible)))
+	}
+	panes := lipgloss.JoinVertical(lipgloss.Left, views...)
+
+	if l.bottom {
+		return lipgloss.JoinVertical(lipgloss.Left, form, panes)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, form, " ", panes)
+}
+
+// paneView renders a single pane inside its border
+func (l *layout) paneView(i int, p Pane, width int, height int) string {
+	frameWidth, frameHeight := l.styles.Pane.GetFrameSize()
+	innerWidth := max(width-frameWidth, 10)
+	innerHeight := max(height-frameHeight-1, 1)
+
+	content := p.Content()
+	if p.Scrollable {
+		vp := &l.viewports[i]
+		vp.Width = innerWidth
+		vp.Height = innerHeight
+		vp.SetContent(content)
+		content = vp.View()
+	} else {
+		content = lipgloss.NewStyle().MaxWidth(innerWidth).MaxHeight(innerHeight).Render(content)
+	}
+
+	return l.styles.Pane.Copy().Width(innerWidth).Render(l.styles.PaneTitle.Render(p.Title) + "\n" + content)
+}
+
+// help lists the key bindings for the step and its panes
+func (l *layout) help() string {
+	items := []string{}
+	for _, b := range l.bindings {
+		if b.Key.Enabled() {
+			items = append(items, b.Key.Help().Key+" "+b.Key.Help().Desc)
+		}
+	}
+	for i, p := range l.panes {
+		if !p.hasToggle() {
+			continue
+		}
+		action := "layout.show"
+		if l.visible[i] {
+			action = "layout.hide"
+		}
+		items = append(items, p.Toggle.Help().Key+" "+l.config.text(action, strings.ToLower(p.Title)))
+	}
+	if l.anyVisible() {
+		items = append(items, l.keys.ToggleLayout.Help().Key+" "+l.keys.ToggleLayout.Help().Desc)
+	}
+	if l.anyScrollable() {
+		items = append(items, l.keys.ScrollUp.Help().Key+"/"+l.keys.ScrollDown.Help().Key+" "+l.config.text("layout.scroll"))
+	}
+	return l.styles.Help.Render(strings.Join(items, " • "))
+}
@@ -0,0 +1,77 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+func TestLayout(t *testing.T) {
+	var body string
+	form := huh.NewForm(huh.NewGroup(huh.NewText().Title("Body").Value(&body)))
+	keys := DefaultKeyMap()
+	l := newLayout(form, []Pane{
+		{Title: "Staged diff", Content: func() string { return "+added" }, Toggle: keys.ToggleDiff, Scrollable: true},
+	}, nil, Config{KeyMap: keys})
+	l.Init()
+
+	l.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	if l.bottom {
+		t.Error("expected panes beside the form on a wide terminal")
+	}
+	if strings.Contains(l.View(), "+added") {
+		t.Error("expected the diff pane to start hidden")
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !strings.Contains(l.View(), "+added") {
+		t.Error("expected the diff pane to be shown after toggling")
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	if !l.bottom {
+		t.Error("expected the panes to move below the form")
+	}
+
+	l.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	if !l.bottom {
+		t.Error("expected panes below the form on a narrow terminal")
+	}
+}
+
+func TestLayoutLeavesScrollKeysToTheForm(t *testing.T) {
+	var body string
+	form := huh.NewForm(huh.NewGroup(huh.NewText().Title("Body").Value(&body)))
+	keys := DefaultKeyMap()
+	keys.ScrollDown = key.NewBinding(key.WithKeys("x"))
+	l := newLayout(form, []Pane{
+		{Title: "Staged diff", Content: func() string { return "+added" }, Toggle: keys.ToggleDiff, Scrollable: true},
+	}, nil, Config{KeyMap: keys})
+	l.Init()
+	l.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	typed := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}
+	l.Update(typed)
+	if !strings.Contains(l.form.View(), "x") {
+		t.Error("expected the scroll key to be typed while no pane can scroll")
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	l.Update(typed)
+	if strings.Contains(l.form.View(), "xx") {
+		t.Error("expected the scroll key to scroll the shown pane")
+	}
+}
+
+func TestHighlightDiffKeepsText(t *testing.T) {
+	diff := "diff --git a/a b/a\n@@ -1 +1 @@\n-old\n+new\n context"
+	got := highlightDiff(DefaultStyles(), diff)
+	for _, line := range strings.Split(diff, "\n") {
+		if !strings.Contains(got, line) {
+			t.Errorf("expected %q in the highlighted diff", line)
+		}
+	}
+}
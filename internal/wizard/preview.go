@@ -0,0 +1,86 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/lint"
+)
+
+// previewPane returns a pane showing the commit message as it stands
+func previewPane(s *State) Pane {
+	return Pane{
+		Title:   s.Config.text("preview.title"),
+		Content: func() string { return preview(s) },
+	}
+}
+
+// preview renders the subject and body with their lengths. Until the message
+// step has filled in the subject it is rendered from the answers so far
+func preview(s *State) string {
+	c := s.Config.Commit()
+	st := s.Config.styles()
+	subject := s.Subject
+	if subject == "" {
+		rendered, err := commit.Subject(c, s.Commit)
+		if err != nil {
+			return st.Error.Render(err.Error())
+		}
+		subject = rendered
+	}
+	body := commit.Body(c, s.Commit)
+	// only the lines written by the user are spell checked, not the trailers
+	written := len(strings.Split(commit.Body(c, commit.Commit{Body: s.Commit.Body}), "\n"))
+
+	lines := []string{
+		st.Subject.Render(highlightMisspellings(st, subject, misspellings(s.Config, subject))),
+		"",
+	}
+	if body == "" {
+		lines = append(lines, st.Muted.Render(s.Config.text("preview.noBody")))
+	}
+	for i, line := range strings.Split(body, "\n") {
+		if body == "" {
+			break
+		}
+		switch {
+		case c.BodyLineLength > 0 && lipgloss.Width(line) > c.BodyLineLength:
+			line = st.Error.Render(line)
+		case i < written:
+			line = highlightMisspellings(st, line, misspellings(s.Config, line))
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", previewCount(s.Config, s.Config.text("preview.subject"), lipgloss.Width(subject), s.Config.CommitTitleCharLimit))
+	if s.Config.CommitBodyCharLimit > 0 {
+		lines = append(lines, previewCount(s.Config, s.Config.text("preview.body"), len(s.Commit.Body), s.Config.CommitBodyCharLimit))
+	}
+	for _, p := range lint.Lint(s.Config.Rules, c, joinMessage(subject, body)) {
+		style := st.Warning
+		if p.Severity == lint.Error {
+			style = st.Error
+		}
+		lines = append(lines, style.Render(p.String()))
+	}
+	if words := misspelledWords(s); len(words) > 0 {
+		style := st.Warning
+		if s.Config.SpellCheck.Block {
+			style = st.Error
+		}
+		lines = append(lines, style.Render(s.Config.text("preview.misspellings", strings.Join(words, ", "))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// previewCount shows a length against its limit, flagging it when over
+func previewCount(c Config, name string, length int, limit int) string {
+	count := fmt.Sprintf("%s: %d/%d", name, length, limit)
+	if length > limit {
+		return c.styles().Error.Render(c.text("preview.tooLong", count))
+	}
+	return c.styles().Muted.Render(count)
+}
@@ -0,0 +1,117 @@
+package wizard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/lint"
+	"github.com/stefanlogue/meteor/pkg/spell"
+)
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    func() *State
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "renders the template before the message step",
+			state: func() *State {
+				return &State{Config: testConfig(), Commit: commit.Commit{Type: "feat", Scope: "api"}}
+			},
+			contains: []string{"feat(api): ", "(no body)", "Subject: 11/48"},
+		},
+		{
+			name: "uses the edited subject and appends coauthors",
+			state: func() *State {
+				return &State{
+					Config:  testConfig(),
+					Subject: "feat: add a login page for the admin area and some more",
+					Commit:  commit.Commit{Body: "Some body", Coauthors: []string{"Jane <jane@example.com>"}},
+				}
+			},
+			contains: []string{"Some body\n\nCo-authored-by: Jane <jane@example.com>", "Subject: 55/48 (too long)"},
+			excludes: []string{"(no body)"},
+		},
+		{
+			name: "counts the body against its limit",
+			state: func() *State {
+				c := testConfig()
+				c.CommitBodyCharLimit = 4
+				return &State{Config: c, Commit: commit.Commit{Type: "fix", Body: "too long"}}
+			},
+			contains: []string{"Body: 8/4 (too long)"},
+		},
+		{
+			name: "lists broken rules",
+			state: func() *State {
+				c := testConfig()
+				c.Rules = lint.Rules{"subject-full-stop": {Severity: lint.Error, When: lint.Never}}
+				return &State{Config: c, Subject: "fix: the login page."}
+			},
+			contains: []string{"error: subject must not end with . [subject-full-stop]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := preview(tt.state())
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("expected %q in preview:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("did not expect %q in preview:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLintError(t *testing.T) {
+	c := testConfig()
+	c.Rules = lint.Rules{
+		"subject-full-stop":  {Severity: lint.Error, When: lint.Never},
+		"subject-imperative": {Severity: lint.Warning, When: lint.Always},
+		"body-empty":         {Severity: lint.Error, When: lint.Never},
+	}
+
+	if err := lintError(c, "fix: fixed the login page.", "body", subjectFields); err == nil || err.Error() != "subject must not end with ." {
+		t.Errorf("got %v, want the full stop error", err)
+	}
+	if err := lintError(c, "fix: fixed the login page", "", subjectFields); err != nil {
+		t.Errorf("expected warnings and body rules to be ignored, got %v", err)
+	}
+	if err := lintError(c, "fix: the login page", "", bodyFields); err == nil {
+		t.Error("expected the empty body to be refused")
+	}
+}
+
+func TestSpelling(t *testing.T) {
+	d := spell.New()
+	d.Add("fix", "the", "login", "page", "Jane")
+	c := testConfig()
+	c.Spelling = d
+
+	s := &State{
+		Config:  c,
+		Subject: "fix: teh login page",
+		Commit:  commit.Commit{Body: "the pgae", Coauthors: []string{"Jane Doe <jane@example.com>"}},
+	}
+	got := preview(s)
+	if !strings.Contains(got, "spelling: possible misspellings: teh, pgae") {
+		t.Errorf("expected the misspellings to be listed, got:\n%s", got)
+	}
+
+	if err := spellingError(c, s.Subject); err != nil {
+		t.Errorf("expected misspellings not to block by default, got %v", err)
+	}
+	c.SpellCheck.Block = true
+	if err := spellingError(c, s.Subject); err == nil || !strings.Contains(err.Error(), `"teh"`) {
+		t.Errorf("got %v, want an error for teh", err)
+	}
+}
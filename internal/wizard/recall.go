@@ -0,0 +1,115 @@
+package wizard
+
+import (
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+)
+
+// recalled is a message written before, from the history or the git log
+type recalled struct {
+	Message string
+	Body    string
+}
+
+// recall suggests messages written before in the message form, and lets the
+// user cycle through them
+type recall struct {
+	state   *State
+	subject *huh.Input
+	body    *huh.Text
+	// messages are the messages in the chosen scope first, newest first
+	messages []recalled
+	// index is the recalled message shown, or -1 for the user's own
+	index int
+	draft recalled
+}
+
+// start prepares the recalled messages for the form's fields and adds them
+// to the subject's suggestions
+func (r *recall) start(s *State, subject *huh.Input, body *huh.Text) {
+	*r = recall{state: s, subject: subject, body: body, messages: recallMessages(s), index: -1}
+	if s.Config.Accessible {
+		return
+	}
+	suggestions := []string{}
+	for _, m := range r.messages {
+		if subject, err := r.render(m); err == nil {
+			suggestions = append(suggestions, subject)
+		}
+	}
+	subject.Suggestions(suggestions)
+}
+
+func (r *recall) bindings(s *State) []Binding {
+	if len(r.messages) == 0 {
+		return nil
+	}
+	keys := s.Config.keys()
+	return []Binding{
+		{Key: keys.PreviousMessage, Action: func() { r.move(1) }},
+		{Key: keys.NextMessage, Action: func() { r.move(-1) }},
+	}
+}
+
+// move shows an older message for a positive step and a newer one for a
+// negative step, coming back to the user's own message after the newest
+func (r *recall) move(step int) {
+	index := r.index + step
+	if index < -1 || index >= len(r.messages) {
+		return
+	}
+	if r.index == -1 {
+		r.draft = recalled{Message: r.state.Subject, Body: r.state.Commit.Body}
+	}
+	r.index = index
+
+	if index == -1 {
+		r.state.Subject = r.draft.Message
+		r.state.Commit.Body = r.draft.Body
+	} else {
+		subject, err := r.render(r.messages[index])
+		if err != nil {
+			return
+		}
+		r.state.Subject = subject
+		r.state.Commit.Body = r.messages[index].Body
+	}
+	r.subject.Value(&r.state.Subject)
+	r.body.Value(&r.state.Commit.Body)
+}
+
+// render returns the subject for a recalled message with the type and scope
+// chosen for this commit
+func (r *recall) render(m recalled) (string, error) {
+	c := r.state.Commit
+	c.Message = m.Message
+	return commit.Subject(r.state.Config.Commit(), c)
+}
+
+// recallMessages returns the messages from the history and, when asked for,
+// the git log, with those in the chosen scope first
+func recallMessages(s *State) []recalled {
+	seen := map[string]bool{}
+	inScope, others := []recalled{}, []recalled{}
+	add := func(scope string, m recalled) {
+		if m.Message == "" || seen[m.Message] {
+			return
+		}
+		seen[m.Message] = true
+		if scope == s.Commit.Scope {
+			inScope = append(inScope, m)
+		} else {
+			others = append(others, m)
+		}
+	}
+	for _, e := range s.Config.History {
+		add(e.Scope, recalled{Message: e.Message, Body: e.Body})
+	}
+	if s.Config.LogMessages != nil {
+		for _, message := range s.Config.LogMessages(s.Commit.Scope) {
+			add(s.Commit.Scope, recalled{Message: message})
+		}
+	}
+	return append(inScope, others...)
+}
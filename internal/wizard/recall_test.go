@@ -0,0 +1,59 @@
+package wizard
+
+import (
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/history"
+)
+
+func TestRecall(t *testing.T) {
+	c := testConfig()
+	c.History = []history.Entry{
+		{Type: "feat", Scope: "api", Message: "add users"},
+		{Type: "chore", Scope: "deps", Message: "bump foo", Body: "from 1 to 2"},
+		{Type: "chore", Scope: "deps", Message: "bump foo"},
+	}
+	c.LogMessages = func(scope string) []string {
+		return []string{"bump bar", "bump foo"}
+	}
+	s := &State{Config: c, Commit: commit.Commit{Type: "chore", Scope: "deps"}}
+	if err := renderSubject(s); err != nil {
+		t.Fatal(err)
+	}
+	s.Subject += "bump baz"
+
+	input, text := messageFields(s.Config, &s.Subject, &s.Commit.Body)
+	r := &recall{}
+	r.start(s, input, text)
+
+	want := []string{"bump foo", "bump bar", "add users"}
+	if len(r.messages) != len(want) {
+		t.Fatalf("expected %d messages, got %v", len(want), r.messages)
+	}
+	for i, m := range want {
+		if r.messages[i].Message != m {
+			t.Errorf("expected message %d to be %q, got %q", i, m, r.messages[i].Message)
+		}
+	}
+
+	r.move(1)
+	if s.Subject != "chore(deps): bump foo" || s.Commit.Body != "from 1 to 2" {
+		t.Errorf("expected the newest message in the scope, got %q and %q", s.Subject, s.Commit.Body)
+	}
+	r.move(1)
+	r.move(1)
+	if s.Subject != "chore(deps): add users" {
+		t.Errorf("expected messages from other scopes to use this commit's type and scope, got %q", s.Subject)
+	}
+	r.move(1)
+	if s.Subject != "chore(deps): add users" {
+		t.Errorf("expected to stop at the oldest message, got %q", s.Subject)
+	}
+	r.move(-1)
+	r.move(-1)
+	r.move(-1)
+	if s.Subject != "chore(deps): bump baz" || s.Commit.Body != "" {
+		t.Errorf("expected the draft back, got %q and %q", s.Subject, s.Commit.Body)
+	}
+}
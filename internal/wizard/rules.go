@@ -0,0 +1,34 @@
+package wizard
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/stefanlogue/meteor/pkg/lint"
+)
+
+// subjectFields and bodyFields are the parts of the message checked by the
+// subject input and the body text area
+var (
+	subjectFields = []string{"header", "subject", "type", "scope"}
+	bodyFields    = []string{"body", "footer"}
+)
+
+// lintError returns the first error from the configured rules for the given
+// parts of the message, so that the form can refuse it
+func lintError(c Config, subject string, body string, fields []string) error {
+	for _, p := range lint.Lint(c.Rules, c.Commit(), joinMessage(subject, body)) {
+		if p.Severity == lint.Error && slices.Contains(fields, p.Field()) {
+			return errors.New(p.Message)
+		}
+	}
+	return nil
+}
+
+// joinMessage returns the full commit message for the subject and body
+func joinMessage(subject string, body string) string {
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + body
+}
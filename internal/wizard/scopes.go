@@ -0,0 +1,55 @@
+package wizard
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+)
+
+// scopeOptions returns the scopes to pick from, or nil when the scope is
+// typed in
+func scopeOptions(s *State) []huh.Option[string] {
+	if allowed := s.Config.Behaviours.Type(s.Commit.Type).AllowedScopes; len(allowed) > 0 {
+		options := []huh.Option[string]{huh.NewOption("none", "")}
+		for _, scope := range allowed {
+			label := scope
+			if description := s.Config.Behaviours.Scopes[scope].Description; description != "" {
+				label = fmt.Sprintf("%s - %s", scope, description)
+			}
+			options = append(options, huh.NewOption(label, scope))
+		}
+		return options
+	}
+	if s.Config.AllowCustomScopes {
+		return nil
+	}
+	return huhOptions(s.Config.Scopes)
+}
+
+// picksScopes reports whether several scopes are ticked in a list rather
+// than picked or typed in as one
+func picksScopes(s *State) bool {
+	return s.Config.MaxScopes > 1 && len(scopeOptions(s)) > 0
+}
+
+// scopeError returns an error if a typed in scope has too many parts, or has
+// one that isn't configured and doesn't match the custom scope pattern
+func scopeError(c Config, scope string) error {
+	scopes := commit.SplitScopes(c.Commit(), scope)
+	if len(scopes) > max(c.MaxScopes, 1) {
+		return errors.New(c.text("scope.tooMany", c.MaxScopes))
+	}
+	if c.CustomScopePattern == nil {
+		return nil
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(c.ScopeStrings, scope) && !c.CustomScopePattern.MatchString(scope) {
+			return errors.New(c.text("scope.invalid", scope))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package wizard
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestScopeError(t *testing.T) {
+	c := testConfig()
+	c.MaxScopes = 2
+	c.ScopeStrings = []string{"API"}
+	c.CustomScopePattern = regexp.MustCompile("^(?:[a-z]+(/[a-z]+)?)$")
+
+	cases := []struct {
+		name    string
+		scope   string
+		wantErr bool
+	}{
+		{"no scope", "", false},
+		{"matching scopes", "api/auth,web", false},
+		{"configured scopes skip the pattern", "API", false},
+		{"too many scopes", "api,web,cli", true},
+		{"scope not matching the pattern", "web,Docs", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := scopeError(c, tc.scope); (err != nil) != tc.wantErr {
+				t.Errorf("scopeError(%q) = %v, want an error: %v", tc.scope, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package wizard
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/stefanlogue/meteor/pkg/spell"
+)
+
+// misspellings returns the unknown words in text, or nothing when spell
+// checking is off
+func misspellings(c Config, text string) []spell.Misspelling {
+	if c.Spelling == nil {
+		return nil
+	}
+	return c.Spelling.Check(text)
+}
+
+// spellingError refuses misspelled words when the spell checker is set to
+// block them
+func spellingError(c Config, text string) error {
+	if !c.SpellCheck.Block {
+		return nil
+	}
+	if ms := misspellings(c, text); len(ms) > 0 {
+		return errors.New(c.text("spelling.unknown", ms[0].Word))
+	}
+	return nil
+}
+
+// misspelledWords lists each unknown word in the message once
+func misspelledWords(s *State) []string {
+	seen := map[string]bool{}
+	words := []string{}
+	for _, text := range []string{s.Subject, s.Commit.Body} {
+		for _, m := range misspellings(s.Config, text) {
+			if !seen[m.Word] {
+				seen[m.Word] = true
+				words = append(words, m.Word)
+			}
+		}
+	}
+	return words
+}
+
+// highlightMisspellings styles each misspelled word in text
+func highlightMisspellings(st *Styles, text string, ms []spell.Misspelling) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range ms {
+		b.WriteString(text[last:m.Offset])
+		b.WriteString(st.Misspelling.Render(m.Word))
+		last = m.Offset + len(m.Word)
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
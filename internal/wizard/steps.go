@@ -0,0 +1,478 @@
+package wizard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/internal/util"
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+// Step is a single stage of the wizard
+type Step struct {
+	Name string
+	// Skip reports whether the step should be bypassed, hooks included
+	Skip func(s *State) bool
+	// Form builds the form shown when running interactively
+	Form func(s *State) *huh.Form
+	// Answer applies pre-recorded answers when running headless
+	Answer func(s *State, a Answers)
+	// Panes returns extra content shown alongside the form
+	Panes func(s *State) []Pane
+	// Bindings returns keys handled while the form is shown, and is called
+	// after Form
+	Bindings func(s *State) []Binding
+	// HidePreview leaves out the preview of the commit message
+	HidePreview bool
+	// Then are steps that follow on from this one, and can depend on its
+	// answers. They run before this step's post hooks
+	Then []*Step
+	Pre  []Hook
+	Post []Hook
+}
+
+var builtinSteps = map[string]func() *Step{
+	"intro":     introStep,
+	"board":     boardStep,
+	"ticket":    ticketStep,
+	"type":      typeStep,
+	"coauthors": coauthorsStep,
+	"message":   messageStep,
+	"confirm":   confirmStep,
+}
+
+// introStep shows the splash screen
+func introStep() *Step {
+	return &Step{
+		Name:        "intro",
+		HidePreview: true,
+		Skip: func(s *State) bool {
+			return !s.Config.ShowIntro
+		},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					splashScreen(s.Config),
+				),
+			)
+		},
+	}
+}
+
+// boardStep asks which board the commit belongs to
+func boardStep() *Step {
+	return &Step{
+		Name: "board",
+		Skip: func(s *State) bool {
+			return len(s.Config.Boards) < 1
+		},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewSelect[string]().
+						Title(s.Config.text("board.title")).
+						Description(s.Config.text("board.description")).
+						Options(huhOptions(s.Config.Boards)...).
+						Value(&s.Commit.Board),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.Board != "" {
+				s.Commit.Board = a.Board
+			}
+		},
+		Post: []Hook{clearTicketNumber},
+	}
+}
+
+// clearTicketNumber drops a prefilled ticket number once no board is chosen,
+// so that the message is rendered without it
+func clearTicketNumber(s *State) error {
+	if !s.HasTicket() {
+		s.Commit.TicketNumber = ""
+	}
+	return nil
+}
+
+// ticketStep asks for the ticket number, prefilled from git where possible
+func ticketStep() *Step {
+	return &Step{
+		Name: "ticket",
+		Skip: func(s *State) bool {
+			return !s.HasTicket()
+		},
+		Pre: []Hook{defaultTicketNumber},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Title(s.Config.text("ticket.title")).
+						Description(s.Config.text("ticket.description")).
+						CharLimit(24).
+						Value(&s.Commit.TicketNumber),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.TicketNumber != "" {
+				s.Commit.TicketNumber = a.TicketNumber
+			}
+		},
+	}
+}
+
+// defaultTicketNumber prefills the ticket number from git, falling back to
+// the board name. A prefilled ticket for the chosen board is kept
+func defaultTicketNumber(s *State) error {
+	if strings.HasPrefix(s.Commit.TicketNumber, s.Commit.Board+"-") {
+		return nil
+	}
+
+	ticketNumber := ""
+	if s.Config.TicketNumber != nil {
+		ticketNumber = s.Config.TicketNumber(s.Commit.Board)
+	}
+
+	if ticketNumber == "" {
+		s.Commit.TicketNumber = fmt.Sprintf("%s-", s.Commit.Board)
+	} else {
+		s.Commit.TicketNumber = ticketNumber
+	}
+	return nil
+}
+
+// typeStep asks for the type of the commit, and then for the rest of the
+// header in the way the type is configured
+func typeStep() *Step {
+	return &Step{
+		Name: "type",
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					typeField(s),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.Type != "" {
+				s.Commit.Type = a.Type
+			}
+		},
+		Then: []*Step{scopeStep(), breakingStep(), requiredTicketStep(), trailersStep()},
+	}
+}
+
+func typeField(s *State) huh.Field {
+	if s.Config.AllowCustomPrefixes {
+		return huh.NewInput().
+			Title(s.Config.text("type.title")).
+			Description(s.Config.text("type.description")).
+			CharLimit(16).
+			Suggestions(s.Config.Prefixes).
+			Value(&s.Commit.Type)
+	}
+	return huh.NewSelect[string]().
+		Title(s.Config.text("type.title")).
+		Description(s.Config.text("type.description")).
+		Options(huhOptions(s.Config.SelectablePrefixes)...).
+		Value(&s.Commit.Type)
+}
+
+// scopeField returns a list of the scopes to pick from if the type allows
+// only some or allowCustomScopes is false, with as many ticked as the config
+// allows, and otherwise a text input with any scopes as suggestions
+func scopeField(s *State, scopes *[]string) huh.Field {
+	options := scopeOptions(s)
+	if len(options) > 0 && s.Config.MaxScopes > 1 {
+		// the options are copied as the field marks the ones that are ticked
+		ticked := []huh.Option[string]{}
+		for _, o := range options {
+			if o.Value != "" {
+				ticked = append(ticked, huh.NewOption(o.Key, o.Value))
+			}
+		}
+		return huh.NewMultiSelect[string]().
+			Title(s.Config.text("scope.title")).
+			Description(s.Config.text("scope.multiDescription", s.Config.MaxScopes)).
+			Value(scopes).
+			Options(ticked...).
+			Limit(s.Config.MaxScopes)
+	}
+	if len(options) > 0 {
+		return huh.NewSelect[string]().
+			Title(s.Config.text("scope.title")).
+			Description(s.Config.text("scope.selectDescription")).
+			Options(options...).
+			Value(&s.Commit.Scope)
+	}
+
+	description := s.Config.text("scope.description")
+	if s.Config.MaxScopes > 1 {
+		description = s.Config.text("scope.customMultiDescription", s.Config.MaxScopes, s.Config.ScopeDelimiter)
+	}
+	input := huh.NewInput().
+		Title(s.Config.text("scope.title")).
+		Description(description).
+		Validate(func(value string) error {
+			return scopeError(s.Config, value)
+		}).
+		Value(&s.Commit.Scope)
+	if len(s.Config.ScopeStrings) > 0 {
+		input.Suggestions(s.Config.ScopeStrings)
+	}
+	return input
+}
+
+// coauthorsStep asks which coauthors to credit
+func coauthorsStep() *Step {
+	return &Step{
+		Name: "coauthors",
+		Skip: func(s *State) bool {
+			return len(s.Config.Coauthors) < 1 && len(s.Commit.Coauthors) < 1
+		},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewMultiSelect[string]().
+						Title(s.Config.text("coauthors.title")).
+						Description(s.Config.text("coauthors.description")).
+						Options(coauthorOptions(s)...).
+						Value(&s.Commit.Coauthors),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if a.Coauthors != nil {
+				s.Commit.Coauthors = a.Coauthors
+			}
+		},
+	}
+}
+
+// huhOptions returns the options from the config for a select
+func huhOptions(options []config.Option) []huh.Option[string] {
+	items := make([]huh.Option[string], len(options))
+	for i, o := range options {
+		items[i] = huh.NewOption(o.Label, o.Value)
+	}
+	return items
+}
+
+// coauthorOptions returns the configured coauthors, plus any prefilled
+// coauthors that aren't configured, after the "no coauthors" option
+func coauthorOptions(s *State) []huh.Option[string] {
+	options := huhOptions(s.Config.Coauthors)
+	for _, coauthor := range s.Commit.Coauthors {
+		known := false
+		for _, o := range options {
+			if o.Value == coauthor {
+				known = true
+				break
+			}
+		}
+		if !known {
+			options = append(options, huh.NewOption(coauthor, coauthor))
+		}
+	}
+	return util.PrependItem(options, huh.NewOption(s.Config.text("coauthors.none"), commit.NoCoauthors))
+}
+
+// messageStep renders the message template and lets the user finish the
+// subject and write a body
+func messageStep() *Step {
+	r := &recall{}
+	return &Step{
+		Name: "message",
+		Pre:  []Hook{renderSubject},
+		Post: []Hook{parseSubject, checkBody},
+		Form: func(s *State) *huh.Form {
+			c := messageConfig(s)
+			input, text := messageFields(c, &s.Subject, &s.Commit.Body)
+			r.start(s, input, text)
+			return messageForm(c, input, text)
+		},
+		Panes:    diffPane,
+		Bindings: r.bindings,
+		Answer: func(s *State, a Answers) {
+			s.Subject += a.Message
+			if a.Body != "" {
+				s.Commit.Body = a.Body
+			}
+		},
+	}
+}
+
+// MessageForm returns the form for editing a subject and body. The subject
+// input is left out when subject is nil
+func MessageForm(c Config, subject *string, body *string) *huh.Form {
+	input, text := messageFields(c, subject, body)
+	return messageForm(c, input, text)
+}
+
+// messageFields returns the subject input, or nil when subject is nil, and
+// the body's text area
+func messageFields(c Config, subject *string, body *string) (*huh.Input, *huh.Text) {
+	var input *huh.Input
+	if subject != nil && c.Accessible {
+		input = accessibleSubjectInput(c, subject, body)
+	} else if subject != nil {
+		input = huh.NewInput().
+			Value(subject).
+			Title(c.text("message.title")).
+			CharLimit(c.CommitTitleCharLimit).
+			Validate(func(value string) error {
+				if err := lintError(c, value, *body, subjectFields); err != nil {
+					return err
+				}
+				return spellingError(c, value)
+			})
+	}
+	text := huh.NewText().
+		Value(body).
+		Title(c.text("body.title")).
+		CharLimit(c.CommitBodyCharLimit).
+		Lines(8).
+		Validate(func(value string) error {
+			header := ""
+			if subject != nil {
+				header = *subject
+			}
+			if err := lintError(c, header, value, bodyFields); err != nil {
+				return err
+			}
+			return spellingError(c, value)
+		})
+	return input, text
+}
+
+func messageForm(c Config, input *huh.Input, text *huh.Text) *huh.Form {
+	fields := []huh.Field{}
+	if input != nil {
+		fields = append(fields, input)
+	}
+	fields = append(fields, text)
+	return huh.NewForm(
+		huh.NewGroup(fields...),
+	).WithKeyMap(&c.keys().KeyMap)
+}
+
+// accessibleSubjectInput asks for the rest of the subject after the rendered
+// message template. Accessible inputs can't be prefilled, so the typed text is
+// appended to the template as it is validated
+func accessibleSubjectInput(c Config, subject *string, body *string) *huh.Input {
+	prefix := *subject
+	var typed string
+	return huh.NewInput().
+		Value(&typed).
+		Title(c.text("message.accessibleTitle", prefix)).
+		Validate(func(value string) error {
+			full := prefix + value
+			if err := lintError(c, full, *body, subjectFields); err != nil {
+				return err
+			}
+			if err := spellingError(c, full); err != nil {
+				return err
+			}
+			*subject = full
+			return nil
+		})
+}
+
+// confirmStep asks whether the user wants to go ahead with the commit
+func confirmStep() *Step {
+	return &Step{
+		Name: "confirm",
+		Form: func(s *State) *huh.Form {
+			fields := []huh.Field{}
+			if words := misspelledWords(s); len(words) > 0 {
+				fields = append(fields, huh.NewNote().
+					Title(s.Config.text("confirm.misspellings")).
+					Description(strings.Join(words, ", ")))
+			}
+			// accessible forms are shown without the preview, so the message
+			// is read out before confirming
+			if s.Config.Accessible {
+				fields = append(fields, huh.NewNote().
+					Title(s.Config.text("confirm.message")).
+					Description(strings.TrimSpace(s.Subject+"\n\n"+commit.Body(s.Config.Commit(), s.Commit))))
+			}
+			if s.Config.Signing != "" {
+				fields = append(fields, huh.NewNote().
+					Title(s.Config.text("confirm.signing")).
+					Description(s.Config.Signing))
+			}
+			fields = append(fields, huh.NewConfirm().
+				Title(s.Config.text("confirm.title")).
+				Affirmative(s.Config.text("confirm.yes")).
+				Negative(s.Config.text("confirm.no")).
+				Value(&s.Confirmed))
+			return huh.NewForm(
+				huh.NewGroup(fields...),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			s.Confirmed = !a.Abort
+		},
+	}
+}
+
+// customStep asks the question defined by a custom step and records the
+// answer as a trailer
+func customStep(cs config.CustomStep) *Step {
+	var value string
+	record := func(s *State) error {
+		s.Values[cs.Name] = value
+		if cs.Trailer != "" && value != "" {
+			s.Commit.Trailers = append(s.Commit.Trailers, commit.Trailer{Key: cs.Trailer, Value: value})
+		}
+		return nil
+	}
+	// a prefilled trailer for this step is taken out of the commit so that
+	// record doesn't add it twice
+	prefill := func(s *State) error {
+		if cs.Trailer == "" {
+			return nil
+		}
+		trailers := []commit.Trailer{}
+		for _, t := range s.Commit.Trailers {
+			if t.Key == cs.Trailer && value == "" {
+				value = t.Value
+				continue
+			}
+			trailers = append(trailers, t)
+		}
+		s.Commit.Trailers = trailers
+		return nil
+	}
+	return &Step{
+		Name: cs.Name,
+		Pre:  []Hook{prefill},
+		Form: func(s *State) *huh.Form {
+			return huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Title(cs.Title).
+						Description(cs.Description).
+						Value(&value),
+				),
+			)
+		},
+		Answer: func(s *State, a Answers) {
+			if v, ok := a.Values[cs.Name]; ok {
+				value = v
+			}
+		},
+		Post: []Hook{record},
+	}
+}
+
+// splashScreen returns a note with a splash screen
+func splashScreen(c Config) *huh.Note {
+	return huh.NewNote().
+		Title("meteor").
+		Description(c.text("intro.description"))
+}
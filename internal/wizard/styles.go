@@ -0,0 +1,46 @@
+package wizard
+
+import "github.com/charmbracelet/lipgloss"
+
+// Styles colour the panes shown beside the forms and what they show
+type Styles struct {
+	Pane      lipgloss.Style
+	PaneTitle lipgloss.Style
+	Help      lipgloss.Style
+	// Muted, Warning and Error mark the lengths and problems in the preview
+	Muted       lipgloss.Style
+	Warning     lipgloss.Style
+	Error       lipgloss.Style
+	Subject     lipgloss.Style
+	Misspelling lipgloss.Style
+	DiffHeader  lipgloss.Style
+	DiffHunk    lipgloss.Style
+	DiffAdded   lipgloss.Style
+	DiffRemoved lipgloss.Style
+}
+
+// DefaultStyles returns the styles used when the config doesn't set any,
+// which use the colours of the terminal's palette
+func DefaultStyles() *Styles {
+	return &Styles{
+		Pane:        lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8")).Padding(0, 1),
+		PaneTitle:   lipgloss.NewStyle().Bold(true),
+		Help:        lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Muted:       lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Warning:     lipgloss.NewStyle().Foreground(lipgloss.Color("3")),
+		Error:       lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		Subject:     lipgloss.NewStyle().Bold(true),
+		Misspelling: lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Underline(true),
+		DiffHeader:  lipgloss.NewStyle().Bold(true),
+		DiffHunk:    lipgloss.NewStyle().Foreground(lipgloss.Color("6")),
+		DiffAdded:   lipgloss.NewStyle().Foreground(lipgloss.Color("2")),
+		DiffRemoved: lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+	}
+}
+
+func (c Config) styles() *Styles {
+	if c.Styles == nil {
+		return DefaultStyles()
+	}
+	return c.Styles
+}
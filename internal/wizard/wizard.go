@@ -0,0 +1,247 @@
+// Package wizard drives the interactive commit flow as a pipeline of steps.
+package wizard
+
+import (
+	"fmt"
+
+	"github.com/stefanlogue/meteor/internal/i18n"
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/history"
+	"github.com/stefanlogue/meteor/pkg/spell"
+)
+
+// Config holds everything the wizard needs to build its steps
+type Config struct {
+	config.Settings
+	SkipBreakingChange bool
+	// Prefill is the commit the wizard starts from, such as when amending
+	Prefill commit.Commit
+	// TicketNumber looks up the ticket number for a board, usually from git
+	TicketNumber func(board string) string
+	// StagedDiff returns the staged changes shown beside the message form
+	StagedDiff func() (string, error)
+	// Spelling is the dictionary the message and body are checked against,
+	// or nil to skip spell checking
+	Spelling *spell.Dictionary
+	// Signing describes how the commit will be signed, or is empty when it
+	// is not
+	Signing string
+	// KeyMap is the key bindings for every form, or nil for the defaults
+	KeyMap *KeyMap
+	// Catalogue is the text of the prompts, or nil for English
+	Catalogue *i18n.Catalogue
+	// Styles colour the panes, or are nil for the defaults
+	Styles *Styles
+	// History is the messages written before, newest first, which are
+	// suggested in the message form
+	History []history.Entry
+	// LogMessages returns the messages of earlier commits in a scope to
+	// suggest as well, or is nil to suggest only the history
+	LogMessages func(scope string) []string
+}
+
+// text returns the prompt text with the given id from the catalogue
+func (c Config) text(id string, args ...any) string {
+	if c.Catalogue == nil {
+		c.Catalogue = i18n.English()
+	}
+	return c.Catalogue.T(id, args...)
+}
+
+// State is shared between every step of a wizard run
+type State struct {
+	Config Config
+	Commit commit.Commit
+	// Subject is the subject line as edited by the user, and Body the final
+	// body with trailers appended
+	Subject   string
+	Body      string
+	Confirmed bool
+	// Values holds the answers given to custom steps, keyed by step name
+	Values map[string]string
+}
+
+// HasTicket reports whether a board has been chosen for the commit
+func (s *State) HasTicket() bool {
+	return len(s.Commit.Board) > 0 && s.Commit.Board != "NONE"
+}
+
+// Behaviour returns the configured behaviour for the commit's type and scopes
+func (s *State) Behaviour() config.Behaviour {
+	return s.Config.Behaviours.For(s.Commit.Type, commit.SplitScopes(s.Config.Commit(), s.Commit.Scope))
+}
+
+// Hook runs before or after a step and may modify the state
+type Hook func(s *State) error
+
+type Wizard struct {
+	config Config
+	steps  []*Step
+}
+
+// New returns a wizard whose steps are ordered according to the config
+func New(c Config) (*Wizard, error) {
+	names := c.Steps
+	if len(names) == 0 {
+		names = defaultStepNames(c.CustomSteps)
+	}
+
+	custom := map[string]config.CustomStep{}
+	for _, cs := range c.CustomSteps {
+		if _, ok := builtinSteps[cs.Name]; ok {
+			return nil, fmt.Errorf("custom step %q clashes with a built-in step", cs.Name)
+		}
+		custom[cs.Name] = cs
+	}
+
+	w := &Wizard{config: c}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			return nil, fmt.Errorf("step %q is listed more than once", name)
+		}
+		seen[name] = true
+
+		if build, ok := builtinSteps[name]; ok {
+			w.steps = append(w.steps, build())
+			continue
+		}
+		if cs, ok := custom[name]; ok {
+			w.steps = append(w.steps, customStep(cs))
+			continue
+		}
+		return nil, fmt.Errorf("unknown step %q", name)
+	}
+
+	for _, required := range []string{"type", "message"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("the %q step cannot be disabled", required)
+		}
+	}
+
+	return w, nil
+}
+
+// defaultStepNames returns the default step order with any custom steps
+// inserted before the message step
+func defaultStepNames(customSteps config.CustomSteps) []string {
+	names := []string{}
+	for _, name := range config.DefaultSteps {
+		if name == "message" {
+			for _, cs := range customSteps {
+				names = append(names, cs.Name)
+			}
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// Steps returns the names of the enabled steps in the order they will run
+func (w *Wizard) Steps() []string {
+	names := make([]string, len(w.steps))
+	for i, step := range w.steps {
+		names[i] = step.Name
+	}
+	return names
+}
+
+// Step returns the named step, or nil if it is not enabled
+func (w *Wizard) Step(name string) *Step {
+	for _, step := range w.steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// AddPreHook registers a hook to run before the named step. It does nothing
+// if the step is not enabled
+func (w *Wizard) AddPreHook(name string, h Hook) {
+	if step := w.Step(name); step != nil {
+		step.Pre = append(step.Pre, h)
+	}
+}
+
+// AddPostHook registers a hook to run after the named step. It does nothing
+// if the step is not enabled
+func (w *Wizard) AddPostHook(name string, h Hook) {
+	if step := w.Step(name); step != nil {
+		step.Post = append(step.Post, h)
+	}
+}
+
+// Run runs every step in order using the given driver and returns the final
+// state, with the body wrapped and trailers appended
+func (w *Wizard) Run(d Driver) (*State, error) {
+	s := &State{
+		Config:    w.config,
+		Commit:    w.config.Prefill,
+		Confirmed: true,
+		Values:    map[string]string{},
+	}
+
+	for _, step := range w.steps {
+		if err := runStep(step, s, d); err != nil {
+			return s, err
+		}
+	}
+
+	finalize(s)
+	return s, nil
+}
+
+// runStep runs a step's hooks around its form, and then any steps that
+// follow on from it before its post hooks
+func runStep(step *Step, s *State, d Driver) error {
+	if step.Skip != nil && step.Skip(s) {
+		return nil
+	}
+	for _, h := range step.Pre {
+		if err := h(s); err != nil {
+			return err
+		}
+	}
+	if err := d.Drive(step, s); err != nil {
+		return err
+	}
+	for _, next := range step.Then {
+		if err := runStep(next, s, d); err != nil {
+			return err
+		}
+	}
+	for _, h := range step.Post {
+		if err := h(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderSubject renders the message template for the commit into the subject
+func renderSubject(s *State) error {
+	subject, err := commit.Subject(s.Config.Commit(), s.Commit)
+	if err != nil {
+		return err
+	}
+	s.Subject = subject
+	return nil
+}
+
+// parseSubject reads the message back out of the subject the user edited
+func parseSubject(s *State) error {
+	parsed, err := commit.Parse(s.Config.Commit(), s.Subject)
+	if err != nil {
+		s.Commit.Message = s.Subject
+		return nil
+	}
+	s.Commit.Message = parsed.Message
+	return nil
+}
+
+// finalize renders the body with its trailers and coauthors
+func finalize(s *State) {
+	s.Body = commit.Body(s.Config.Commit(), s.Commit)
+}
@@ -0,0 +1,347 @@
+package wizard
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func testConfig() Config {
+	return Config{Settings: config.Default()}
+}
+
+func TestRunGolden(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  func(c *Config)
+		answers Answers
+	}{
+		{
+			name:    "simple",
+			answers: Answers{Type: "feat", Message: "add a thing"},
+		},
+		{
+			name:    "scope_and_breaking_change",
+			answers: Answers{Type: "fix", Scope: "api", IsBreakingChange: true, Message: "drop v1 routes", Body: "v1 has been deprecated for a year"},
+		},
+		{
+			name: "skip_breaking_change",
+			config: func(c *Config) {
+				c.SkipBreakingChange = true
+			},
+			answers: Answers{Type: "fix", IsBreakingChange: true, Message: "not breaking"},
+		},
+		{
+			name: "ticket_from_git",
+			config: func(c *Config) {
+				c.Boards = []config.Option{config.NewOption("COMP", "COMP")}
+				c.TicketNumber = func(board string) string { return board + "-123" }
+			},
+			answers: Answers{Board: "COMP", Type: "chore", Message: "tidy up"},
+		},
+		{
+			name: "ticket_without_git",
+			config: func(c *Config) {
+				c.Boards = []config.Option{config.NewOption("COMP", "COMP")}
+			},
+			answers: Answers{Board: "COMP", TicketNumber: "COMP-9", Type: "feat", Message: "manual ticket"},
+		},
+		{
+			name: "none_board",
+			config: func(c *Config) {
+				c.Boards = []config.Option{config.NewOption("COMP", "COMP"), config.NewOption("NONE", "NONE")}
+			},
+			answers: Answers{Board: "NONE", Type: "docs", Message: "no ticket"},
+		},
+		{
+			name: "wrapped_body_with_coauthors",
+			config: func(c *Config) {
+				c.CommitBodyLineLength = 20
+				c.Coauthors = []config.Option{config.NewOption("Jane <jane@example.com>", "Jane <jane@example.com>")}
+			},
+			answers: Answers{
+				Type:      "feat",
+				Message:   "pair on it",
+				Body:      "This is a long text that should be wrapped correctly.",
+				Coauthors: []string{"Jane <jane@example.com>"},
+			},
+		},
+		{
+			name: "custom_step_trailer",
+			config: func(c *Config) {
+				c.CustomSteps = config.CustomSteps{{Name: "reviewer", Title: "Reviewer", Trailer: "Reviewed-by"}}
+			},
+			answers: Answers{Type: "test", Message: "cover edge cases", Body: "Adds tests.", Values: map[string]string{"reviewer": "Bob"}},
+		},
+		{
+			name: "prefilled",
+			config: func(c *Config) {
+				c.Boards = []config.Option{config.NewOption("COMP", "COMP")}
+				c.TicketNumber = func(board string) string { return board + "-999" }
+				c.CustomSteps = config.CustomSteps{{Name: "reviewer", Title: "Reviewer", Trailer: "Reviewed-by"}}
+				c.Prefill = commit.Commit{
+					Board:            "COMP",
+					TicketNumber:     "COMP-42",
+					Type:             "fix",
+					Scope:            "api",
+					Message:          "handle timeouts",
+					Body:             "Retries twice.",
+					IsBreakingChange: true,
+					Coauthors:        []string{"Jane <jane@example.com>"},
+					Trailers:         []commit.Trailer{{Key: "Reviewed-by", Value: "Bob"}, {Key: "Refs", Value: "#1"}},
+				}
+			},
+			answers: Answers{Message: " properly"},
+		},
+		{
+			name: "type_skips_breaking_change",
+			config: func(c *Config) {
+				c.Behaviours.Types = map[string]config.Prefix{"docs": {T: "docs", Behaviour: config.Behaviour{SkipBreakingChange: true}}}
+			},
+			answers: Answers{Type: "docs", IsBreakingChange: true, Message: "fix typos"},
+		},
+		{
+			name: "type_requires_ticket",
+			config: func(c *Config) {
+				c.Behaviours.Types = map[string]config.Prefix{"release": {T: "release", Behaviour: config.Behaviour{RequireTicket: true}}}
+			},
+			answers: Answers{Type: "release", TicketNumber: "REL-7", Message: "ship 1.2"},
+		},
+		{
+			name: "type_defaults",
+			config: func(c *Config) {
+				c.Behaviours.Types = map[string]config.Prefix{"feat": {
+					T: "feat",
+					Behaviour: config.Behaviour{
+						BodyTemplate: "Why:",
+						Trailers:     []config.Trailer{{Key: "Changelog", Value: "added"}, {Key: "Refs"}},
+					},
+					DefaultScope: "web",
+				}}
+			},
+			answers: Answers{Type: "feat", Message: "add the export", Values: map[string]string{"Refs": "#12"}},
+		},
+		{
+			name: "scope_behaviour",
+			config: func(c *Config) {
+				c.Behaviours.Types = map[string]config.Prefix{"feat": {T: "feat", Behaviour: config.Behaviour{
+					Trailers: []config.Trailer{{Key: "Changelog", Value: "added"}},
+				}}}
+				c.Behaviours.Scopes = map[string]config.Scope{"api": {Name: "api", Behaviour: config.Behaviour{
+					RequireBody: true,
+					Trailers:    []config.Trailer{{Key: "Changelog", Value: "api"}},
+				}}}
+			},
+			answers: Answers{Type: "feat", Scope: "api", Message: "add the export", Body: "Streams the rows."},
+		},
+		{
+			name: "multiple_scopes",
+			config: func(c *Config) {
+				c.MaxScopes = 2
+				c.Scopes = (&config.Scopes{{Name: "api"}, {Name: "web"}}).Options()
+			},
+			answers: Answers{Type: "feat", Scope: "web,api", Message: "add the export"},
+		},
+		{
+			name: "nested_scope",
+			config: func(c *Config) {
+				scopes := config.Scopes{{Name: "api", Behaviour: config.Behaviour{Trailers: []config.Trailer{{Key: "Team", Value: "platform"}}}, Scopes: config.Scopes{{Name: "auth"}}}}
+				c.Scopes = scopes.Options()
+				c.Behaviours.Scopes = scopes.Behaviours()
+			},
+			answers: Answers{Type: "fix", Scope: "api/auth", Message: "refresh expired tokens"},
+		},
+		{
+			name: "prefilled_none_board",
+			config: func(c *Config) {
+				c.Boards = []config.Option{config.NewOption("COMP", "COMP"), config.NewOption("NONE", "NONE")}
+				c.Prefill = commit.Commit{Board: "COMP", TicketNumber: "COMP-42", Type: "fix", Message: "handle timeouts"}
+			},
+			answers: Answers{Board: "NONE", Message: " properly"},
+		},
+		{
+			name:    "aborted",
+			answers: Answers{Type: "feat", Message: "never mind", Abort: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := testConfig()
+			if tc.config != nil {
+				tc.config(&c)
+			}
+			w, err := New(c)
+			if err != nil {
+				t.Fatalf("New() returned error: %v", err)
+			}
+			s, err := w.Run(Headless{Answers: tc.answers})
+			if err != nil {
+				t.Fatalf("Run() returned error: %v", err)
+			}
+
+			got := s.Subject + "\n\n" + s.Body
+			if !s.Confirmed {
+				got = "# aborted\n" + got
+			}
+			assertGolden(t, filepath.Join("testdata", tc.name+".golden"), got)
+		})
+	}
+}
+
+func TestNewStepOrder(t *testing.T) {
+	cases := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{
+			name:   "defaults",
+			config: Config{},
+			want:   config.DefaultSteps,
+		},
+		{
+			name:   "reordered and disabled",
+			config: Config{Settings: config.Settings{Steps: []string{"type", "board", "ticket", "message"}}},
+			want:   []string{"type", "board", "ticket", "message"},
+		},
+		{
+			name:   "custom steps go before message by default",
+			config: Config{Settings: config.Settings{CustomSteps: config.CustomSteps{{Name: "reviewer"}}}},
+			want:   []string{"intro", "board", "ticket", "type", "coauthors", "reviewer", "message", "confirm"},
+		},
+		{
+			name: "custom steps can be placed",
+			config: Config{Settings: config.Settings{
+				Steps:       []string{"reviewer", "type", "message"},
+				CustomSteps: config.CustomSteps{{Name: "reviewer"}},
+			}},
+			want: []string{"reviewer", "type", "message"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := New(tc.config)
+			if err != nil {
+				t.Fatalf("New() returned error: %v", err)
+			}
+			if got := w.Steps(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Steps() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	errorCases := []struct {
+		name   string
+		config Config
+	}{
+		{"unknown step", Config{Settings: config.Settings{Steps: []string{"type", "message", "nope"}}}},
+		{"duplicate step", Config{Settings: config.Settings{Steps: []string{"type", "type", "message"}}}},
+		{"type disabled", Config{Settings: config.Settings{Steps: []string{"message"}}}},
+		{"message disabled", Config{Settings: config.Settings{Steps: []string{"type"}}}},
+		{"custom step clashes", Config{Settings: config.Settings{CustomSteps: config.CustomSteps{{Name: "board"}}}}},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.config); err == nil {
+				t.Error("expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestRequirements(t *testing.T) {
+	c := testConfig()
+	c.Behaviours.Types = map[string]config.Prefix{
+		"release": {T: "release", Behaviour: config.Behaviour{RequireTicket: true}},
+		"revert":  {T: "revert", Behaviour: config.Behaviour{Trailers: []config.Trailer{{Key: "Refs"}}}},
+		"feat":    {T: "feat", Behaviour: config.Behaviour{RequireBody: true}},
+	}
+	cases := []struct {
+		name    string
+		config  func(c *Config)
+		answers Answers
+	}{
+		{"missing ticket", nil, Answers{Type: "release", Message: "ship it"}},
+		{"missing trailer", nil, Answers{Type: "revert", Message: "undo it"}},
+		{"missing body", nil, Answers{Type: "feat", Message: "add it"}},
+		{"custom scope not matching the pattern", func(c *Config) {
+			c.CustomScopePattern = regexp.MustCompile("^(?:[a-z]+)$")
+		}, Answers{Type: "fix", Scope: "API", Message: "fix it"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := c
+			if tc.config != nil {
+				tc.config(&c)
+			}
+			w, err := New(c)
+			if err != nil {
+				t.Fatalf("New() returned error: %v", err)
+			}
+			if _, err := w.Run(Headless{Answers: tc.answers}); err == nil {
+				t.Error("expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestHooks(t *testing.T) {
+	w, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	var order []string
+	w.AddPreHook("message", func(s *State) error {
+		order = append(order, "pre:"+s.Subject)
+		return nil
+	})
+	w.AddPostHook("message", func(s *State) error {
+		order = append(order, "post:"+s.Subject+"|"+s.Commit.Message)
+		s.Subject = "overridden"
+		return nil
+	})
+	w.AddPostHook("missing", func(s *State) error {
+		t.Error("hook on a missing step should never run")
+		return nil
+	})
+
+	s, err := w.Run(Headless{Answers: Answers{Type: "feat", Message: "hooked"}})
+	if err != nil {
+		t.Fatalf("Run() returned error: %v", err)
+	}
+
+	want := []string{"pre:feat: ", "post:feat: hooked|hooked"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("hooks ran as %v, want %v", order, want)
+	}
+	if s.Subject != "overridden" {
+		t.Errorf("Subject = %q, want %q", s.Subject, "overridden")
+	}
+}
+
+func assertGolden(t testing.TB, path, got string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("error writing golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("expected %q, got %q", string(want), got)
+	}
+}
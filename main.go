@@ -2,39 +2,42 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
-	"text/template"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
-	"github.com/fatih/color"
+	"github.com/stefanlogue/meteor/internal/i18n"
 	"github.com/stefanlogue/meteor/internal/util"
+	"github.com/stefanlogue/meteor/internal/wizard"
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
 	cfg "github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/spell"
 
 	"github.com/atotto/clipboard"
-	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/huh"
 	"github.com/spf13/afero"
 	flag "github.com/spf13/pflag"
 )
 
-type Commit struct {
-	Board            string
-	TicketNumber     string
-	Type             string
-	Scope            string
-	Message          string
-	Body             string
-	Coauthors        []string
-	IsBreakingChange bool
-}
-
 var (
 	version            = "dev"
 	debugMode          bool
 	skipIntro          bool
 	skipBreakingChange bool
+	amend              bool
+	all                bool
+	noVerify           bool
+	accessible         bool
+	commitSigning      signing
+	formatFlag         string
+	statsSince         string
+	statsPeriod        string
+	templateFlag       string
+	prOutput           string
+	sequenceEditor     string
+	messageEditor      string
 	FS                 afero.Fs     = afero.NewOsFs()
 	AFS                *afero.Afero = &afero.Afero{Fs: FS}
 )
@@ -42,7 +45,13 @@ var (
 const (
 	AsGitEditor = "as-git-editor"
 	ErrorString = "Error: %s"
-	ShiftTab    = "shift+tab"
+)
+
+// keyMap, catalogue and paneStyles are set from the config once it is loaded
+var (
+	keyMap     = wizard.DefaultKeyMap()
+	catalogue  = i18n.English()
+	paneStyles = wizard.DefaultStyles()
 )
 
 func init() {
@@ -51,6 +60,19 @@ func init() {
 	flag.BoolVarP(&skipIntro, "skip-intro", "s", false, "skip intro splash")
 	flag.BoolVarP(&debugMode, "debug", "D", false, "enable debug mode")
 	flag.BoolVarP(&skipBreakingChange, "skip-breaking-change", "b", false, "skip breaking change prompt")
+	flag.BoolVar(&amend, "amend", false, "amend the last commit, starting from its message")
+	flag.BoolVarP(&all, "all", "a", false, "commit all changed files, skipping the staging step")
+	flag.BoolVarP(&noVerify, "no-verify", "n", false, "skip the configured checks and git's commit hooks")
+	flag.BoolVar(&accessible, "accessible", false, "ask every question on its own line, for screen readers")
+	flag.StringVar(&formatFlag, "format", "", "output format for stats and notes")
+	flag.StringVar(&statsSince, "since", "", "only include commits after this date in stats, such as \"6 months ago\"")
+	flag.StringVar(&statsPeriod, "period", "month", "group stats by week, month or year")
+	flag.StringVar(&templateFlag, "template", "", "template file to render the output with")
+	flag.StringVar(&prOutput, "output", "", "file to write pr-text to instead of stdout")
+	flag.StringVar(&sequenceEditor, SequenceEditor, "", "used as GIT_SEQUENCE_EDITOR when rewording")
+	flag.StringVar(&messageEditor, MessageEditor, "", "used as GIT_EDITOR when rewording")
+	_ = flag.CommandLine.MarkHidden(SequenceEditor)
+	_ = flag.CommandLine.MarkHidden(MessageEditor)
 	flag.Parse()
 	if util.IsFlagPassed("version") {
 		fmt.Printf("meteor version %s\n", version)
@@ -72,6 +94,20 @@ func init() {
 }
 
 func main() {
+	// meteor runs itself as git's editors when rewording an older commit
+	if sequenceEditor != "" {
+		if err := runSequenceEditor(sequenceEditor, flag.Arg(0)); err != nil {
+			fail(ErrorString, err)
+		}
+		return
+	}
+	if messageEditor != "" {
+		if err := runMessageEditor(messageEditor, flag.Arg(0)); err != nil {
+			fail(ErrorString, err)
+		}
+		return
+	}
+
 	gitPath, err := getGitPath()
 	if err != nil {
 		fail(ErrorString, err)
@@ -86,224 +122,164 @@ func main() {
 		fail("Could not change directory: %s", err)
 	}
 
-	config, err := loadConfig(AFS)
+	config, err := cfg.Load(AFS)
 	if err != nil {
 		fail(ErrorString, err)
 	}
 
-	var newCommit Commit
-	theme := huh.ThemeCatppuccin()
-	if config.ShowIntro && (util.IsFlagPassed("skip-intro") && !skipIntro) {
-		introForm := huh.NewForm(
-			huh.NewGroup(
-				splashScreen(),
-			),
-		)
-		if err := introForm.Run(); err != nil {
+	coAuthors := config.Coauthors
+	if config.ReadContributorsFromGit {
+		additional, err := getComitters([]string{})
+		if err != nil {
 			fail(ErrorString, err)
+		} else {
+			for _, s := range additional {
+				coAuthors = append(coAuthors, cfg.NewOption(s, s))
+			}
 		}
 	}
-	if len(config.Boards) > 0 {
-		boardForm := huh.NewForm(
-			huh.NewGroup(
-				huh.NewSelect[string]().
-					Title("Board").
-					Description("Select the board for this commit").
-					Options(config.Boards...).
-					Value(&newCommit.Board),
-			).WithHideFunc(func() bool {
-				return len(config.Boards) < 1
-			}),
-		).WithTheme(theme)
-
-		err = boardForm.Run()
-		if err != nil {
-			fail(ErrorString, err)
+
+	config.Coauthors = coAuthors
+	config.ShowIntro = config.ShowIntro && (util.IsFlagPassed("skip-intro") && !skipIntro)
+	accessible = accessible || config.Accessible
+	config.Accessible = accessible
+	theme := newTheme(config.Theme)
+	messages = newMessageStyles(config.Theme)
+	paneStyles = newPaneStyles(theme, messages)
+	if keyMap, err = wizard.NewKeyMap(config.Keys); err != nil {
+		fail(ErrorString, err)
+	}
+	locale := config.Locale
+	if locale == "" {
+		locale = i18n.EnvironmentLocale()
+	}
+	if catalogue, err = i18n.Load(locale, config.Strings); err != nil {
+		fail(ErrorString, err)
+	}
+	keyMap = keyMap.Translate(catalogue)
+	args := flag.Args()
+	if len(args) > 0 && !util.IsFlagPassed(AsGitEditor) {
+		switch args[0] {
+		case "stats":
+			runStats(config, args[1:])
+			return
+		case "notes":
+			runNotes(config, args[1:])
+			return
+		case "pr-text":
+			runPRText(config, args[1:])
+			return
 		}
 	}
 
-	if len(newCommit.Board) > 0 && newCommit.Board != "NONE" {
-		ticketNumber := getGitTicketNumber(newCommit.Board)
+	if config.Sign {
+		args = addSignArg(args)
+	}
+	commitSigning = getSigning(config, args)
+	if err := commitSigning.verify(); err != nil {
+		fail("\n%s\n%s\n", messages.errorf("%s", catalogue.T("error", err)), messages.warnf("%s", strings.Join(commitSigning.hints(), "\n")))
+	}
 
-		if ticketNumber == "" {
-			newCommit.TicketNumber = fmt.Sprintf("%s-", newCommit.Board)
-		} else {
-			newCommit.TicketNumber = ticketNumber
+	if len(args) > 0 && !util.IsFlagPassed(AsGitEditor) {
+		switch args[0] {
+		case "fixup":
+			runFixup(config, theme, args[1:])
+			return
+		case "revert":
+			runRevert(config, theme, args[1:])
+			return
+		case "squash-message":
+			runSquashMessage(config, theme, args[1:])
+			return
 		}
+	}
 
-		ticketNumberForm := huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().
-					Title("Ticket number").
-					Description("The ticket number associated with this commit").
-					CharLimit(24).
-					Value(&newCommit.TicketNumber),
-			).WithHideFunc(func() bool {
-				return len(config.Boards) < 1
-			}),
-		).WithTheme(theme)
-
-		err = ticketNumberForm.Run()
+	// when amending or rewording, the wizard starts from the existing message
+	var prefill cmt.Commit
+	prefillRev := "HEAD"
+	rewordTarget := ""
+	if len(args) > 0 && args[0] == "reword" {
+		if len(args) < 2 {
+			fail("Usage: meteor reword <rev>")
+		}
+		rewordTarget, err = resolveCommit(args[1])
 		if err != nil {
 			fail(ErrorString, err)
 		}
+		if !isAncestorOfHead(rewordTarget) {
+			fail(ErrorString, fmt.Sprintf("%s is not on the current branch", args[1]))
+		}
+		head, err := resolveCommit("HEAD")
+		if err != nil {
+			fail(ErrorString, err)
+		}
+		args = args[2:]
+		prefillRev = rewordTarget
+		if rewordTarget == head {
+			// rewording HEAD is an amend which leaves the staged changes alone
+			rewordTarget = ""
+			amend = true
+			args = append(args, "--only")
+		}
 	}
-
-	var typeInput huh.Field
-	if config.AllowCustomPrefixes {
-		typeInput = huh.NewInput().
-			Title("Type").
-			Description("Select the type of change that you're committing").
-			CharLimit(16).
-			Suggestions(config.Prefixes).
-			Value(&newCommit.Type)
-	} else {
-		typeInput = huh.NewSelect[string]().
-			Title("Type").
-			Description("Select the type of change that you're committing").
-			Options(config.SelectablePrefixes...).
-			Value(&newCommit.Type)
-	}
-
-	// if the user has specified scopes in their config and allowCustomScopes is true, use a text input with suggestions
-	// if the user has specified scopes in their config and allowCustomScopes is false, use a select input
-	// otherwise use a text input
-	var scopeInput huh.Field
-	if config.AllowCustomScopes && len(config.ScopeStrings) > 0 {
-		scopeInput = huh.NewInput().
-			Title("Scope").
-			Description("Specify a scope of the changes").
-			CharLimit(16).
-			Suggestions(config.ScopeStrings).
-			Value(&newCommit.Scope)
-	} else if len(config.Scopes) > 0 {
-		scopeInput = huh.NewSelect[string]().
-			Title("Scope").
-			Description("Choose a scope for the changes").
-			Options(config.Scopes...).
-			Value(&newCommit.Scope)
-	} else {
-		scopeInput = huh.NewInput().
-			Title("Scope").
-			Description("Specify a scope of the changes").
-			CharLimit(16).
-			Value(&newCommit.Scope)
-	}
-
-	// if the user has specified for asking breaking change, add a confirm input to the main group
-	var mainGroup *huh.Group
-	if skipBreakingChange {
-		mainGroup = huh.NewGroup(typeInput, scopeInput)
-	} else {
-		mainGroup = huh.NewGroup(
-			typeInput,
-			huh.NewConfirm().
-				Title("Breaking Change").
-				Description("Is this a breaking change?").
-				Affirmative("Yes!").
-				Negative("Nope.").
-				Value(&newCommit.IsBreakingChange),
-			scopeInput,
-		)
-	}
-	coAuthors := config.Coauthors
-	if config.ReadContributorsFromGit {
-		additional, err := getComitters([]string{})
+	if amend || rewordTarget != "" {
+		prefill, err = prefillFrom(config, prefillRev)
 		if err != nil {
 			fail(ErrorString, err)
-		} else {
-			for _, s := range additional {
-				coAuthors = append(coAuthors, huh.NewOption(s, s))
-			}
 		}
 	}
-	if len(coAuthors) > 0 {
-		coAuthors = util.PrependItem(coAuthors, huh.NewOption("no coauthors", "none"))
-	}
-	mainForm := huh.NewForm(
-		mainGroup,
-		huh.NewGroup(
-			huh.NewMultiSelect[string]().
-				Title("Coauthors").
-				Description("Select any coauthors for this commit").
-				Options(coAuthors...).
-				Value(&newCommit.Coauthors),
-		).WithHideFunc(func() bool {
-			return len(coAuthors) < 1
-		}),
-	).WithTheme(theme)
-
-	err = mainForm.Run()
-	if err != nil {
-		fail(ErrorString, err)
+	if amend {
+		args = append(args, "--amend")
+	}
+	if noVerify {
+		args = append(args, "--no-verify")
 	}
 
-	var tmpl *template.Template
-	if len(newCommit.Board) > 0 && newCommit.Board != "NONE" {
-		tmpl = template.Must(template.New("message").Parse(config.MessageWithTicketTemplate))
-	} else {
-		tmpl = template.Must(template.New("message").Parse(config.MessageTemplate))
+	// make sure there is something to commit before starting the wizard
+	if rewordTarget == "" && !util.IsFlagPassed(AsGitEditor) && shouldStage(args) {
+		staged := runStaging(theme)
+		if prefill.Scope == "" {
+			prefill.Scope = inferScope(staged, config.ScopeStrings, config.AllowCustomScopes)
+		}
 	}
-	buf := new(bytes.Buffer)
-	err = tmpl.Execute(buf, newCommit)
-	if err != nil {
-		fail(ErrorString, err)
+	if rewordTarget == "" && !util.IsFlagPassed(AsGitEditor) {
+		ok, staged := runGuard(config.Guard, theme, all)
+		if staged {
+			// the changes --all would commit are staged now, without the
+			// files the user left out
+			all = false
+		}
+		if !ok || !runChecks(config, theme) {
+			fmt.Printf("\n%s\n\n", messages.errorf("%s", catalogue.T("commit.aborted")))
+			return
+		}
 	}
-	newCommit.Message = buf.String()
-
-	doesWantToCommit := true
-	messageForm := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Value(&newCommit.Message).
-				Title("Message").
-				CharLimit(config.CommitTitleCharLimit),
-			huh.NewText().
-				Value(&newCommit.Body).
-				Title("Body").
-				CharLimit(config.CommitBodyCharLimit).
-				Lines(8),
-		),
-		huh.NewGroup(
-			huh.NewConfirm().
-				Title("Ready to commit?").
-				Affirmative("Yes!").
-				Negative("No.").
-				Value(&doesWantToCommit),
-		),
-	).WithKeyMap(&huh.KeyMap{
-		Quit: key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
-		Text: huh.TextKeyMap{
-			Next:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "next")),
-			NewLine: key.NewBinding(key.WithKeys("alt+enter", "ctrl+j"), key.WithHelp("alt+enter / ctrl+j", "new line")),
-			Editor:  key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "open editor")),
-			Prev:    key.NewBinding(key.WithKeys(ShiftTab), key.WithHelp(ShiftTab, "back")),
-		},
-		Input: huh.InputKeyMap{
-			Next: key.NewBinding(key.WithKeys("enter", "tab"), key.WithHelp("enter / tab", "next")),
-		},
-		Confirm: huh.ConfirmKeyMap{
-			Toggle: key.NewBinding(key.WithKeys("left", "right", "h", "l"), key.WithHelp("left / right", "toggle")),
-			Prev:   key.NewBinding(key.WithKeys(ShiftTab), key.WithHelp(ShiftTab, "back")),
-			Submit: key.NewBinding(key.WithKeys("enter", "tab"), key.WithHelp("enter / tab", "submit")),
-		},
-	}).WithTheme(theme)
-
-	err = messageForm.Run()
-	if err != nil {
-		fail(ErrorString, err)
+	if all {
+		args = append(args, "--all")
 	}
 
-	if config.CommitBodyLineLength >= minimumCommitBodyLineLength {
-		newCommit.Body = wordWrap(newCommit.Body, config.CommitBodyLineLength)
+	state, err := newWizard(config, prefill).Run(wizard.Interactive{Theme: theme})
+	if err != nil {
+		fail(ErrorString, err)
 	}
+	doesWantToCommit := state.Confirmed
 
-	if len(newCommit.Coauthors) > 0 {
-		newCommit.Body = newCommit.Body + cfg.BuildCoAuthorString(newCommit.Coauthors)
+	if rewordTarget != "" {
+		if !doesWantToCommit {
+			fmt.Printf("\n%s\n\n", messages.errorf("%s", catalogue.T("reword.aborted")))
+			return
+		}
+		if err := rewordCommit(rewordTarget, state.Subject, state.Body); err != nil {
+			fail(
+				"\n%s\n%s\n\n",
+				messages.errorf("%s", catalogue.T("reword.failed", err)),
+				messages.warnf("%s", catalogue.T("rebase.stopped")),
+			)
+		}
+		rememberMessage(config.History, state)
+		return
 	}
 
-	args := flag.Args()
-
 	var commitFile string
 
 	// If we're operating in GIT_EDITOR="meteor --as-git-editor" mode, the first argument is the path (.git/COMMIT_EDITMSG)
@@ -313,7 +289,11 @@ func main() {
 		args = args[1:]
 	}
 
-	rawCommitCommand, printableCommitCommand := buildCommitCommand(newCommit.Message, newCommit.Body, args)
+	rawCommitCommand, printableCommitCommand := buildCommitCommand(state.Subject, state.Body, args)
+
+	if doesWantToCommit {
+		doesWantToCommit = checkSecrets(config, theme)
+	}
 
 	if util.IsFlagPassed(AsGitEditor) {
 		// We intent to do the commit
@@ -321,21 +301,13 @@ func main() {
 			// Write the commit message file (.git/COMMIT_EDITMSG) in same format as git would have,
 			// the message, a blank line, and a body - if body is empty, trailing newlines will be removed
 
-			if err := os.WriteFile(commitFile, bytes.TrimRight([]byte(newCommit.Message+"\n\n"+newCommit.Body), "/n"), os.FileMode(os.O_WRONLY)); err != nil {
+			if err := os.WriteFile(commitFile, bytes.TrimRight([]byte(state.Subject+"\n\n"+state.Body), "/n"), os.FileMode(os.O_WRONLY)); err != nil {
 				// In case of failure, give the regular error-ish output to the end-user so no inputs are lost
-				writeToClipboard(printableCommitCommand)
-
-				fail(
-					"\n%s\n%s\n\n%s\n\n",
-					color.RedString(fmt.Sprintf("It looks like the commit failed.\nError: %s", err)),
-					color.YellowString("To run it again without going through meteor's wizard, simply run the following command (I've copied it to your clipboard!):"),
-					color.BlueString(printableCommitCommand),
-				)
-
-				return
+				commitFailed(printableCommitCommand, err)
 			}
 
 			// we wrote the commit message file, nothing left for us to do, success!
+			rememberMessage(config.History, state)
 
 			return
 		}
@@ -343,12 +315,7 @@ func main() {
 		// end-user decided to abort the commit, which mean we don't write the git commit message file (.git/COMMIT_EDITMSG)
 		// which will make git abort the operation
 
-		writeToClipboard(printableCommitCommand)
-		fmt.Printf(
-			"\n%s\n\n%s\n%s\n\n",
-			color.RedString("Commit aborted."),
-			color.YellowString("I've copied the following command to your clipboard, so you can run it again later:"),
-			color.BlueString(printableCommitCommand))
+		commitAborted(printableCommitCommand)
 
 		return
 	}
@@ -356,24 +323,107 @@ func main() {
 	if doesWantToCommit {
 		err := commit(rawCommitCommand)
 		if err != nil {
-			writeToClipboard(printableCommitCommand)
-			fail(
-				"\n%s\n%s\n\n%s\n\n",
-				color.RedString(fmt.Sprintf("It looks like the commit failed.\nError: %s", err)),
-				color.YellowString("To run it again without going through meteor's wizard, simply run the following command (I've copied it to your clipboard!):"),
-				color.BlueString(printableCommitCommand),
-			)
+			commitFailed(printableCommitCommand, err)
 		}
+		rememberMessage(config.History, state)
 	} else {
-		writeToClipboard(printableCommitCommand)
-		fmt.Printf(
-			"\n%s\n\n%s\n%s\n\n",
-			color.RedString("Commit aborted."),
-			color.YellowString("I've copied the following command to your clipboard, so you can run it again later:"),
-			color.BlueString(printableCommitCommand))
+		commitAborted(printableCommitCommand)
 	}
 }
 
+// newWizard returns the commit wizard for the config, starting from prefill
+func newWizard(config cfg.Settings, prefill cmt.Commit) *wizard.Wizard {
+	w, err := wizard.New(wizard.Config{
+		Settings:           config,
+		SkipBreakingChange: skipBreakingChange,
+		Prefill:            prefill,
+		TicketNumber:       getGitTicketNumber,
+		StagedDiff:         getStagedDiff,
+		Spelling:           newDictionary(config),
+		Signing:            commitSigning.String(),
+		KeyMap:             keyMap,
+		Catalogue:          catalogue,
+		Styles:             paneStyles,
+		History:            loadHistory(config.History),
+		LogMessages:        logMessages(config),
+	})
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	return w
+}
+
+// newDictionary returns the spell checker's dictionary with the project's
+// words added, or nil if spell checking is off
+func newDictionary(config cfg.Settings) *spell.Dictionary {
+	if !config.SpellCheck.Enabled {
+		return nil
+	}
+	d := spell.English()
+	for _, path := range config.SpellCheck.Dictionaries {
+		if err := d.LoadFiles(path); err != nil {
+			log.Error("Error loading dictionary", "path", path, "error", err)
+		}
+	}
+	d.Add(config.Dictionary...)
+	d.Add(config.Prefixes...)
+	// nested scopes are checked word by word
+	for _, scope := range config.ScopeStrings {
+		d.Add(strings.Split(scope, "/")...)
+	}
+	if diff, err := getStagedDiff(); err == nil {
+		d.Add(spell.Identifiers(diff)...)
+	}
+	return d
+}
+
+// commitFailed copies the commit command to the clipboard so that no input is
+// lost, then exits
+func commitFailed(printableCommitCommand string, err error) {
+	writeToClipboard(printableCommitCommand)
+	var commitErr *commitError
+	if errors.As(err, &commitErr) && isSigningFailure(commitErr.Stderr) {
+		fail(
+			"\n%s\n%s\n\n%s\n\n%s\n\n",
+			messages.errorf("%s", catalogue.T("signing.failed")),
+			messages.warnf("%s", strings.Join(commitSigning.hints(), "\n")),
+			messages.warnf("%s", catalogue.T("commit.retrySigned")),
+			messages.infof("%s", printableCommitCommand),
+		)
+	}
+	fail(
+		"\n%s\n%s\n\n%s\n\n",
+		messages.errorf("%s", catalogue.T("commit.failed", err)),
+		messages.warnf("%s", catalogue.T("commit.retry")),
+		messages.infof("%s", printableCommitCommand),
+	)
+}
+
+// commitAborted copies the commit command to the clipboard so that it can be
+// run later
+func commitAborted(printableCommitCommand string) {
+	writeToClipboard(printableCommitCommand)
+	fmt.Printf(
+		"\n%s\n\n%s\n%s\n\n",
+		messages.errorf("%s", catalogue.T("commit.aborted")),
+		messages.warnf("%s", catalogue.T("commit.copied")),
+		messages.infof("%s", printableCommitCommand))
+}
+
+// prefillFrom parses the message of the given revision into a commit for the
+// wizard to start from
+func prefillFrom(config cfg.Settings, rev string) (cmt.Commit, error) {
+	message, err := getCommitMessage(rev)
+	if err != nil {
+		return cmt.Commit{}, err
+	}
+	prefill, err := cmt.Parse(config.Commit(), message)
+	if err != nil && !errors.Is(err, cmt.ErrNoMatch) {
+		return cmt.Commit{}, err
+	}
+	return prefill, nil
+}
+
 // writeToClipboard writes a string to the clipboard
 func writeToClipboard(s string) {
 	if err := clipboard.WriteAll(s); err != nil {
@@ -381,13 +431,6 @@ func writeToClipboard(s string) {
 	}
 }
 
-// splashScreen returns a note with a splash screen
-func splashScreen() *huh.Note {
-	return huh.NewNote().
-		Title("meteor").
-		Description("A highly customisable command line tool\nfor writing conventional commit messages")
-}
-
 // fail prints an error message and exits with a non-zero exit code
 func fail(format string, args ...interface{}) {
 	_, _ = fmt.Fprintf(os.Stderr, format+"\n", args...)
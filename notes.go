@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/notes"
+)
+
+// runNotes writes release notes for a range of commits, grouped by ticket.
+// Any further arguments are passed on to git log
+func runNotes(config cfg.Settings, args []string) {
+	if len(args) < 1 {
+		fail("Usage: meteor notes <range> [--format markdown|html|text] [--template <file>]")
+	}
+	format := notes.Markdown
+	if formatFlag != "" {
+		var err error
+		if format, err = notes.ParseFormat(formatFlag); err != nil {
+			fail(ErrorString, err)
+		}
+	}
+	log, err := getLog(append([]string{"--no-merges", args[0]}, args[1:]...)...)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	if len(log) == 0 {
+		fail(ErrorString, "there are no commits in "+args[0])
+	}
+
+	entries := make([]notes.Entry, len(log))
+	for i, e := range log {
+		entries[i] = notes.Entry{Hash: e.Hash, Author: e.Author, Date: e.Date, Message: e.Message}
+	}
+	boards := boardNames(config)
+	n := notes.Build(config.Commit(), entries, func(message string) string {
+		return findTicket(message, boards)
+	})
+	n.Range = args[0]
+	if err := n.Render(os.Stdout, format, templateFlag); err != nil {
+		fail(ErrorString, err)
+	}
+}
+
+// findTicket returns the first ticket number for any of the boards in a
+// commit message
+func findTicket(message string, boards []string) string {
+	for _, line := range strings.Split(message, "\n") {
+		for _, board := range boards {
+			if ticket := strings.TrimSpace(getTicketNumberFromString(line, board)); ticket != "" {
+				return strings.ToUpper(ticket)
+			}
+		}
+	}
+	return ""
+}
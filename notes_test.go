@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestFindTicket(t *testing.T) {
+	cases := []struct {
+		Desc    string
+		message string
+		want    string
+	}{
+		{"it should find a ticket in the subject", "ENG-12: <fix> the thing", "ENG-12"},
+		{"it should find a ticket in the body", "fix: the thing\n\nRefs ops-3", "OPS-3"},
+		{"it should be empty without a ticket", "fix: the thing", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, findTicket(tc.message, []string{"ENG", "OPS"}))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package commit builds and parses commit messages using meteor's message
+// templates, without depending on the terminal UI.
+package commit
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/stefanlogue/meteor/internal/util"
+)
+
+// NoCoauthors is the coauthor value used to explicitly select no coauthors
+const NoCoauthors = "none"
+
+// MinimumBodyLineLength is the shortest line length the body will be wrapped at
+const MinimumBodyLineLength = 20
+
+// Commit is the structured form of a commit message
+type Commit struct {
+	Board            string
+	TicketNumber     string
+	Type             string
+	Scope            string
+	Message          string
+	Body             string
+	Coauthors        []string
+	Trailers         []Trailer
+	IsBreakingChange bool
+}
+
+// Trailer is a "Key: value" line at the end of the commit body
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// Config controls how commits are rendered and parsed. The templates use the
+// Go template syntax produced by config.ConvertTemplate
+type Config struct {
+	MessageTemplate           string
+	MessageWithTicketTemplate string
+	BodyLineLength            int
+	// ScopeDelimiter separates the scopes of a commit, of which there can be
+	// up to MaxScopes
+	ScopeDelimiter string
+	MaxScopes      int
+}
+
+// Render returns the subject line and body for the commit
+func Render(c Config, cm Commit) (string, string, error) {
+	subject, err := Subject(c, cm)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, Body(c, cm), nil
+}
+
+// Subject renders the subject line for the commit, using the ticket template
+// when the commit has a ticket number
+func Subject(c Config, cm Commit) (string, error) {
+	text := c.MessageTemplate
+	if cm.TicketNumber != "" {
+		text = c.MessageWithTicketTemplate
+	}
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing message template: %w", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, cm); err != nil {
+		return "", fmt.Errorf("error rendering message template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// Body returns the commit body wrapped to the configured line length, followed
+// by the trailers and any coauthors
+func Body(c Config, cm Commit) string {
+	body := cm.Body
+	if c.BodyLineLength >= MinimumBodyLineLength {
+		body = util.WordWrap(body, c.BodyLineLength)
+	}
+
+	trailers := trailerLines(cm)
+	if len(trailers) == 0 {
+		return body
+	}
+	if body == "" {
+		return strings.Join(trailers, "\n")
+	}
+	return body + "\n\n" + strings.Join(trailers, "\n")
+}
+
+// trailerLines returns the formatted trailers followed by a Co-authored-by
+// trailer for each coauthor. Selecting NoCoauthors drops all coauthors
+func trailerLines(cm Commit) []string {
+	lines := []string{}
+	for _, t := range cm.Trailers {
+		lines = append(lines, fmt.Sprintf("%s: %s", t.Key, t.Value))
+	}
+	for _, coauthor := range cm.Coauthors {
+		if coauthor == NoCoauthors {
+			return lines[:len(cm.Trailers)]
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", coAuthoredBy, coauthor))
+	}
+	return lines
+}
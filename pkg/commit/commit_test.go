@@ -0,0 +1,96 @@
+package commit_test
+
+import (
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name        string
+		commit      commit.Commit
+		lineLength  int
+		wantSubject string
+		wantBody    string
+	}{
+		{
+			name:        "type and message",
+			commit:      commit.Commit{Type: "feat", Message: "add a thing"},
+			wantSubject: "feat: add a thing",
+		},
+		{
+			name:        "scope and breaking change",
+			commit:      commit.Commit{Type: "fix", Scope: "api", IsBreakingChange: true, Message: "drop v1", Body: "It is gone."},
+			wantSubject: "fix(api)!: drop v1",
+			wantBody:    "It is gone.",
+		},
+		{
+			name:        "ticket number uses the ticket template",
+			commit:      commit.Commit{Board: "COMP", TicketNumber: "COMP-12", Type: "chore", Message: "tidy"},
+			wantSubject: "COMP-12: <chore> tidy",
+		},
+		{
+			name:        "body is wrapped",
+			commit:      commit.Commit{Type: "docs", Message: "wrap", Body: "This is a long text that should be wrapped correctly."},
+			lineLength:  20,
+			wantSubject: "docs: wrap",
+			wantBody:    "This is a long text\nthat should be\nwrapped correctly.",
+		},
+		{
+			name:        "line length under the minimum is ignored",
+			commit:      commit.Commit{Type: "docs", Message: "wrap", Body: "This is a long text"},
+			lineLength:  5,
+			wantSubject: "docs: wrap",
+			wantBody:    "This is a long text",
+		},
+		{
+			name: "trailers and coauthors",
+			commit: commit.Commit{
+				Type:      "feat",
+				Message:   "pair",
+				Body:      "Together.",
+				Trailers:  []commit.Trailer{{Key: "Refs", Value: "#12"}},
+				Coauthors: []string{"Jane <jane@example.com>"},
+			},
+			wantSubject: "feat: pair",
+			wantBody:    "Together.\n\nRefs: #12\nCo-authored-by: Jane <jane@example.com>",
+		},
+		{
+			name:        "coauthors without a body",
+			commit:      commit.Commit{Type: "feat", Message: "pair", Coauthors: []string{"Jane <jane@example.com>"}},
+			wantSubject: "feat: pair",
+			wantBody:    "Co-authored-by: Jane <jane@example.com>",
+		},
+		{
+			name:        "no coauthors option drops coauthors",
+			commit:      commit.Commit{Type: "feat", Message: "solo", Coauthors: []string{"Jane <jane@example.com>", commit.NoCoauthors}},
+			wantSubject: "feat: solo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config.Default().Commit()
+			c.BodyLineLength = tt.lineLength
+			subject, body, err := commit.Render(c, tt.commit)
+			if err != nil {
+				t.Fatalf("Render() returned error: %v", err)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("Render() subject = %q, want %q", subject, tt.wantSubject)
+			}
+			if body != tt.wantBody {
+				t.Errorf("Render() body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRender_InvalidTemplate(t *testing.T) {
+	c := commit.Config{MessageTemplate: "{{.Type"}
+	if _, _, err := commit.Render(c, commit.Commit{Type: "feat"}); err == nil {
+		t.Error("expected an error, but got nil")
+	}
+}
@@ -0,0 +1,154 @@
+package commit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const coAuthoredBy = "Co-authored-by"
+
+// ErrNoMatch is returned by Parse when the subject matches neither template.
+// The returned commit still holds the subject as its message, and the body
+var ErrNoMatch = errors.New("subject does not match the message templates")
+
+var breakingFooterRegex = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+var trailerRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*): (.+)$`)
+
+// templateTokens maps each template action produced by config.ConvertTemplate
+// to the pattern that matches its rendered output
+var templateTokens = []struct {
+	action  string
+	pattern string
+}{
+	{"{{if .Scope}}({{.Scope}}){{end}}", `(?:\((?P<scope>[^()]+)\))?`},
+	{"{{if .IsBreakingChange}}!{{end}}", `(?P<breaking>!)?`},
+	{"{{.TicketNumber}}", `(?P<ticket>[^\s()<>!:]+)`},
+	{"{{.Type}}", `(?P<type>[^\s()<>!:]+)`},
+	{"{{.Scope}}", `(?P<scope>[^\s()<>!:]*)`},
+	{"{{.Message}}", `(?P<message>.*)`},
+}
+
+// Parse turns a full commit message back into a commit. The ticket template is
+// tried before the plain one, and trailers in the final paragraph of the body
+// are split out into coauthors and trailers
+func Parse(c Config, message string) (Commit, error) {
+	message = strings.TrimLeft(message, "\n")
+	subject, rest, _ := strings.Cut(message, "\n")
+	// only the message is trimmed, so that "feat: " still matches with an
+	// empty message
+	subject = strings.TrimLeft(strings.TrimRight(subject, "\r"), " \t")
+
+	var cm Commit
+	cm.Body, cm.Coauthors, cm.Trailers = splitTrailers(rest)
+
+	for _, t := range []string{c.MessageWithTicketTemplate, c.MessageTemplate} {
+		if t == "" {
+			continue
+		}
+		re, err := templateRegex(t)
+		if err != nil {
+			return cm, err
+		}
+		match := re.FindStringSubmatch(subject)
+		if match == nil {
+			continue
+		}
+		for i, name := range re.SubexpNames() {
+			switch name {
+			case "ticket":
+				cm.TicketNumber = match[i]
+			case "type":
+				cm.Type = match[i]
+			case "scope":
+				if match[i] != "" {
+					cm.Scope = match[i]
+				}
+			case "breaking":
+				cm.IsBreakingChange = cm.IsBreakingChange || match[i] != ""
+			case "message":
+				cm.Message = strings.TrimSpace(match[i])
+			}
+		}
+		if i := strings.LastIndex(cm.TicketNumber, "-"); i > 0 {
+			cm.Board = cm.TicketNumber[:i]
+		}
+		return cm, nil
+	}
+
+	cm.Message = strings.TrimSpace(subject)
+	return cm, ErrNoMatch
+}
+
+// templateRegex builds a regular expression matching the subjects that the
+// template renders
+func templateRegex(t string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for len(t) > 0 {
+		matched := false
+		for _, token := range templateTokens {
+			if strings.HasPrefix(t, token.action) {
+				b.WriteString(token.pattern)
+				t = t[len(token.action):]
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if strings.HasPrefix(t, "{{") {
+			end := strings.Index(t, "}}")
+			if end < 0 {
+				end = len(t) - 2
+			}
+			return nil, fmt.Errorf("unsupported template action %q", t[:end+2])
+		}
+		b.WriteString(regexp.QuoteMeta(t[:1]))
+		t = t[1:]
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// splitTrailers separates the final paragraph of the body if every line in it
+// is a trailer
+func splitTrailers(body string) (string, []string, []Trailer) {
+	lines := strings.Split(body, "\n")
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == end {
+		return strings.TrimSpace(body), nil, nil
+	}
+
+	var coauthors []string
+	var trailers []Trailer
+	for _, line := range lines[start:end] {
+		match := trailerRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			return strings.TrimSpace(body), nil, nil
+		}
+		if strings.EqualFold(match[1], coAuthoredBy) {
+			coauthors = append(coauthors, match[2])
+			continue
+		}
+		trailers = append(trailers, Trailer{Key: match[1], Value: match[2]})
+	}
+
+	return strings.TrimSpace(strings.Join(lines[:start], "\n")), coauthors, trailers
+}
+
+// HasBreakingFooter reports whether a message has a "BREAKING CHANGE:" or
+// "BREAKING-CHANGE:" footer
+func HasBreakingFooter(message string) bool {
+	return breakingFooterRegex.MatchString(message)
+}
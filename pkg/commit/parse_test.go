@@ -0,0 +1,120 @@
+package commit_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    commit.Commit
+	}{
+		{
+			name:    "type and message",
+			message: "feat: add a thing",
+			want:    commit.Commit{Type: "feat", Message: "add a thing"},
+		},
+		{
+			name:    "scope and breaking change",
+			message: "fix(api)!: drop v1\n\nIt is gone.\n",
+			want:    commit.Commit{Type: "fix", Scope: "api", IsBreakingChange: true, Message: "drop v1", Body: "It is gone."},
+		},
+		{
+			name:    "ticket",
+			message: "COMP-12(ui): <chore> tidy",
+			want:    commit.Commit{Board: "COMP", TicketNumber: "COMP-12", Type: "chore", Scope: "ui", Message: "tidy"},
+		},
+		{
+			name:    "trailers and coauthors",
+			message: "feat: pair\n\nTogether.\n\nRefs: #12\nCo-authored-by: Jane <jane@example.com>",
+			want: commit.Commit{
+				Type:      "feat",
+				Message:   "pair",
+				Body:      "Together.",
+				Trailers:  []commit.Trailer{{Key: "Refs", Value: "#12"}},
+				Coauthors: []string{"Jane <jane@example.com>"},
+			},
+		},
+		{
+			name:    "legacy coauthor block",
+			message: "feat: pair\n\nTogether.\n\n\n\t\nCo-authored-by: Jane <jane@example.com>",
+			want:    commit.Commit{Type: "feat", Message: "pair", Body: "Together.", Coauthors: []string{"Jane <jane@example.com>"}},
+		},
+		{
+			name:    "final paragraph that is not all trailers stays in the body",
+			message: "feat: words\n\nNote: this is prose\nand continues here",
+			want:    commit.Commit{Type: "feat", Message: "words", Body: "Note: this is prose\nand continues here"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commit.Parse(config.Default().Commit(), tt.message)
+			if err != nil {
+				t.Fatalf("Parse() returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_CustomTemplate(t *testing.T) {
+	tmpl, err := config.ConvertTemplate("[@ticket] @type(@scope): @message")
+	if err != nil {
+		t.Fatalf("ConvertTemplate() returned error: %v", err)
+	}
+	c := commit.Config{MessageTemplate: config.DefaultMessageTemplate, MessageWithTicketTemplate: tmpl}
+
+	got, err := commit.Parse(c, "[PROJ-7] fix(db)!: lock rows")
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	want := commit.Commit{Board: "PROJ", TicketNumber: "PROJ-7", Type: "fix", Scope: "db", IsBreakingChange: true, Message: "lock rows"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParse_NoMatch(t *testing.T) {
+	got, err := commit.Parse(config.Default().Commit(), "Merge branch 'main'\n\nsome body")
+	if !errors.Is(err, commit.ErrNoMatch) {
+		t.Errorf("Parse() error = %v, want %v", err, commit.ErrNoMatch)
+	}
+	if got.Message != "Merge branch 'main'" || got.Body != "some body" {
+		t.Errorf("Parse() = %+v, want the subject as the message and the body kept", got)
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	c := config.Default().Commit()
+	original := commit.Commit{
+		Board:            "COMP",
+		TicketNumber:     "COMP-99",
+		Type:             "refactor",
+		Scope:            "core",
+		Message:          "split the wizard",
+		Body:             "Steps are now pluggable.",
+		Trailers:         []commit.Trailer{{Key: "Reviewed-by", Value: "Bob"}},
+		Coauthors:        []string{"Jane <jane@example.com>"},
+		IsBreakingChange: true,
+	}
+	subject, body, err := commit.Render(c, original)
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+	got, err := commit.Parse(c, subject+"\n\n"+body)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, original) {
+		t.Errorf("Parse(Render()) = %+v, want %+v", got, original)
+	}
+}
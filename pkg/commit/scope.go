@@ -0,0 +1,36 @@
+package commit
+
+import (
+	"slices"
+	"strings"
+)
+
+// SplitScopes returns the scopes written in a commit's scope, which is only
+// split when a commit can have more than one
+func SplitScopes(c Config, scope string) []string {
+	if scope == "" {
+		return nil
+	}
+	if c.MaxScopes <= 1 {
+		return []string{scope}
+	}
+	scopes := []string{}
+	for _, part := range strings.Split(scope, c.ScopeDelimiter) {
+		if part = strings.TrimSpace(part); part != "" && !slices.Contains(scopes, part) {
+			scopes = append(scopes, part)
+		}
+	}
+	return scopes
+}
+
+// JoinScopes returns the commit's scope for the scopes, leaving out any given
+// twice
+func JoinScopes(c Config, scopes []string) string {
+	unique := []string{}
+	for _, scope := range scopes {
+		if scope != "" && !slices.Contains(unique, scope) {
+			unique = append(unique, scope)
+		}
+	}
+	return strings.Join(unique, c.ScopeDelimiter)
+}
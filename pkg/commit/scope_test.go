@@ -0,0 +1,34 @@
+package commit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitScopes(t *testing.T) {
+	single := Config{ScopeDelimiter: ",", MaxScopes: 1}
+	multiple := Config{ScopeDelimiter: "+", MaxScopes: 3}
+
+	cases := []struct {
+		name   string
+		config Config
+		scope  string
+		want   []string
+	}{
+		{"empty", multiple, "", nil},
+		{"single scopes are not split", single, "api,web", []string{"api,web"}},
+		{"split on the delimiter", multiple, "api + web", []string{"api", "web"}},
+		{"repeats are dropped", multiple, "api+web+api", []string{"api", "web"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SplitScopes(tc.config, tc.scope); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("SplitScopes() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if got := JoinScopes(multiple, []string{"api", "web", "api"}); got != "api+web" {
+		t.Errorf("JoinScopes() = %q, want %q", got, "api+web")
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import "strings"
+
+// Behaviour changes the wizard for commits of one type or in one scope
+type Behaviour struct {
+	SkipBreakingChange bool `json:"skipBreakingChange"`
+	RequireTicket      bool `json:"requireTicket"`
+	RequireBody        bool `json:"requireBody"`
+	// BodyTemplate is the text the body starts with when it is empty
+	BodyTemplate string `json:"bodyTemplate"`
+	// Trailers are added to the commit. A trailer without a value is asked
+	// for and must be given
+	Trailers []Trailer `json:"trailers"`
+}
+
+// Trailer is a "Key: value" line added to the commit body
+type Trailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Behaviours holds the settings of the configured types and scopes by name
+type Behaviours struct {
+	Types  map[string]Prefix
+	Scopes map[string]Scope
+}
+
+// Type returns the settings of a type, which are empty if it isn't configured
+func (b Behaviours) Type(t string) Prefix {
+	return b.Types[t]
+}
+
+// For returns the behaviour of a commit with the given type and scopes. A
+// nested scope also has the behaviour of its parents. A requirement of any of
+// them applies, and the body template and trailers of a scope replace those
+// of the type or a parent
+func (b Behaviours) For(t string, scopes []string) Behaviour {
+	behaviour := b.Types[t].Behaviour
+	for _, scope := range scopes {
+		parts := strings.Split(scope, "/")
+		for i := range parts {
+			behaviour = behaviour.merge(b.Scopes[strings.Join(parts[:i+1], "/")].Behaviour)
+		}
+	}
+	return behaviour
+}
+
+// merge returns the behaviour with other applied over it
+func (b Behaviour) merge(other Behaviour) Behaviour {
+	merged := Behaviour{
+		SkipBreakingChange: b.SkipBreakingChange || other.SkipBreakingChange,
+		RequireTicket:      b.RequireTicket || other.RequireTicket,
+		RequireBody:        b.RequireBody || other.RequireBody,
+		BodyTemplate:       b.BodyTemplate,
+	}
+	if other.BodyTemplate != "" {
+		merged.BodyTemplate = other.BodyTemplate
+	}
+
+	for _, trailer := range b.Trailers {
+		replaced := false
+		for _, o := range other.Trailers {
+			if o.Key == trailer.Key {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Trailers = append(merged.Trailers, trailer)
+		}
+	}
+	merged.Trailers = append(merged.Trailers, other.Trailers...)
+	return merged
+}
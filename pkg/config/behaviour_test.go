@@ -0,0 +1,48 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestBehaviours(t *testing.T) {
+	c := New()
+	data := `{
+		"prefixes": [
+			{"type": "docs", "description": "documentation", "skipBreakingChange": true},
+			{"type": "feat", "description": "a feature", "requireBody": true, "defaultScope": "web", "allowedScopes": ["web", "api"],
+			 "bodyTemplate": "Why:", "trailers": [{"key": "Changelog", "value": "added"}, {"key": "Refs"}]}
+		],
+		"scopes": [
+			{"name": "api", "requireTicket": true, "bodyTemplate": "Endpoints:", "trailers": [{"key": "Changelog", "value": "api"}],
+			 "scopes": [{"name": "auth", "trailers": [{"key": "Refs", "value": "#1"}]}]}
+		]
+	}`
+	if err := json.Unmarshal([]byte(data), c); err != nil {
+		t.Fatal(err)
+	}
+	b := c.Settings().Behaviours
+
+	feat := b.Type("feat")
+	cases := []struct {
+		Desc string
+		want string
+		got  string
+	}{
+		{"it should read the type's settings", "web [web api]", fmt.Sprint(feat.DefaultScope, " ", feat.AllowedScopes)},
+		{"it should skip breaking changes for the type", "true", fmt.Sprint(b.For("docs", nil).SkipBreakingChange)},
+		{"it should be empty for an unknown type", "false", fmt.Sprint(b.For("fix", nil).RequireBody)},
+		{"it should use the type's body template", "Why:", b.For("feat", []string{"web"}).BodyTemplate},
+		{"it should prefer the scope's body template", "Endpoints:", b.For("feat", []string{"api"}).BodyTemplate},
+		{"it should combine the requirements", "true true", fmt.Sprint(b.For("feat", []string{"api"}).RequireBody, b.For("feat", []string{"api"}).RequireTicket)},
+		{"it should replace the type's trailers with the scope's", "[{Refs } {Changelog api}]", fmt.Sprint(b.For("feat", []string{"api"}).Trailers)},
+		{"it should apply the parents of a nested scope", "Endpoints: [{Changelog api} {Refs #1}]", fmt.Sprint(b.For("feat", []string{"api/auth"}).BodyTemplate, " ", b.For("feat", []string{"api/auth"}).Trailers)},
+		{"it should apply every scope", "true", fmt.Sprint(b.For("fix", []string{"web", "api"}).RequireTicket)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqual(t, tc.want, tc.got)
+		})
+	}
+}
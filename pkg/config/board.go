@@ -1,22 +1,20 @@
 package config
 
-import "github.com/charmbracelet/huh"
-
 type Board struct {
 	Name string `json:"name"`
 }
 
 type Boards []Board
 
-func (p *Boards) Options() []huh.Option[string] {
+func (p *Boards) Options() []Option {
 	boards := []Board(*p)
 
 	if len(boards) == 0 {
 		return nil
 	}
-	items := []huh.Option[string]{}
+	items := []Option{}
 	for _, board := range boards {
-		items = append(items, huh.NewOption(board.Name, board.Name))
+		items = append(items, NewOption(board.Name, board.Name))
 	}
 	return items
 }
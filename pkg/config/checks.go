@@ -0,0 +1,24 @@
+package config
+
+// Check is a command, such as a formatter, linter or test suite, run against
+// the staged files before the wizard
+type Check struct {
+	Name string `json:"name"`
+	// Run is a shell command. "{files}" is replaced with the staged files
+	// matching Paths
+	Run string `json:"run"`
+	// Paths lists globs for the staged files the check applies to. When it
+	// is set the check is skipped if no staged file matches
+	Paths []string `json:"paths"`
+	// Formatter checks rewrite files, so they run one at a time before the
+	// other checks and their changes can be staged afterwards
+	Formatter bool `json:"formatter"`
+}
+
+// Label returns the name of the check, falling back to its command
+func (c Check) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Run
+}
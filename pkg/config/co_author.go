@@ -3,8 +3,6 @@ package config
 import (
 	"fmt"
 	"strings"
-
-	"github.com/charmbracelet/huh"
 )
 
 type CoAuthor struct {
@@ -15,22 +13,25 @@ type CoAuthor struct {
 
 type CoAuthors []CoAuthor
 
-func (p *CoAuthors) Options() []huh.Option[string] {
+func (p *CoAuthors) Options() []Option {
 	coAuthors := []CoAuthor(*p)
 
 	if len(coAuthors) == 0 {
 		return nil
 	}
-	items := []huh.Option[string]{}
+	items := []Option{}
 	for _, coauthor := range coAuthors {
 		desc := fmt.Sprintf("%s <%s>", coauthor.Name, coauthor.Email)
-		items = append(items, huh.NewOption(desc, desc))
+		items = append(items, NewOption(desc, desc))
 	}
 	return items
 }
 
 // BuildCoauthorString takes a slice of selected coauthors and returns a formatted
 // string which Github recognises
+//
+// Deprecated: use commit.Body, which renders coauthors alongside any other
+// trailers
 func BuildCoAuthorString(coauthors []string) string {
 	var s strings.Builder
 	s.WriteString(`
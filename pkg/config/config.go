@@ -8,22 +8,43 @@ import (
 	"os"
 
 	"github.com/charmbracelet/log"
+
+	"github.com/stefanlogue/meteor/pkg/lint"
 )
 
 type Config struct {
-	ShowIntro                 *bool     `json:"showIntro"`
-	CommitTitleCharLimit      *int      `json:"commitTitleCharLimit"`
-	CommitBodyCharLimit       *int      `json:"commitBodyCharLimit"`
-	CommitBodyLineLength      *int      `json:"commitBodyLineLength"`
-	MessageTemplate           *string   `json:"messageTemplate"`
-	MessageWithTicketTemplate *string   `json:"messageWithTicketTemplate"`
-	Prefixes                  Prefixes  `json:"prefixes"`
-	Coauthors                 CoAuthors `json:"coauthors"`
-	Boards                    Boards    `json:"boards"`
-	Scopes                    Scopes    `json:"scopes"`
-	ReadContributorsFromGit   *bool     `json:"readContributorsFromGit"`
-	AllowCustomPrefixes       *bool     `json:"allowCustomPrefixes"`
-	AllowCustomScopes         *bool     `json:"allowCustomScopes"`
+	ShowIntro                 *bool       `json:"showIntro"`
+	CommitTitleCharLimit      *int        `json:"commitTitleCharLimit"`
+	CommitBodyCharLimit       *int        `json:"commitBodyCharLimit"`
+	CommitBodyLineLength      *int        `json:"commitBodyLineLength"`
+	MessageTemplate           *string     `json:"messageTemplate"`
+	MessageWithTicketTemplate *string     `json:"messageWithTicketTemplate"`
+	Prefixes                  Prefixes    `json:"prefixes"`
+	Coauthors                 CoAuthors   `json:"coauthors"`
+	Boards                    Boards      `json:"boards"`
+	Scopes                    Scopes      `json:"scopes"`
+	ReadContributorsFromGit   *bool       `json:"readContributorsFromGit"`
+	AllowCustomPrefixes       *bool       `json:"allowCustomPrefixes"`
+	AllowCustomScopes         *bool       `json:"allowCustomScopes"`
+	CustomScopePattern        *string     `json:"customScopePattern"`
+	ScopeDelimiter            *string     `json:"scopeDelimiter"`
+	MaxScopes                 *int        `json:"maxScopes"`
+	Steps                     []string    `json:"steps"`
+	CustomSteps               CustomSteps `json:"customSteps"`
+	Rules                     lint.Rules  `json:"rules"`
+	SpellCheck                SpellCheck  `json:"spellCheck"`
+	Dictionary                []string    `json:"dictionary"`
+	Secrets                   SecretScan  `json:"secrets"`
+	Guard                     Guard       `json:"guard"`
+	Checks                    []Check     `json:"checks"`
+	Sign                      bool        `json:"sign"`
+	TicketURL                 string      `json:"ticketUrl"`
+	Theme                     Theme       `json:"theme"`
+	Keys                      Keys        `json:"keys"`
+	Accessible                bool        `json:"accessible"`
+	Locale                    string      `json:"locale"`
+	Strings                   Strings     `json:"strings"`
+	History                   History     `json:"history"`
 }
 
 // New returns a new Config
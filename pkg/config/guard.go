@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Guard configures the checks on staged files run before the wizard
+type Guard struct {
+	// MaxFileSize is the largest file that can be committed, or 0 for no limit
+	MaxFileSize ByteSize `json:"maxFileSize"`
+	// Forbidden lists globs for files that must never be committed
+	Forbidden []string `json:"forbidden"`
+	// BinaryPaths lists the globs binary files may be committed under. When
+	// it is not set binary files are allowed anywhere
+	BinaryPaths []string `json:"binaryPaths"`
+	// LFS checks that files tracked by Git LFS in .gitattributes are staged
+	// as LFS pointers. It is on by default
+	LFS *bool `json:"lfs"`
+}
+
+// CheckLFS reports whether the Git LFS check is on
+func (g Guard) CheckLFS() bool {
+	return g.LFS == nil || *g.LFS
+}
+
+// ByteSize is a number of bytes, written in the config as a number or a
+// string with a unit such as "500KB" or "5MB"
+type ByteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*b = ByteSize(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("size must be a number of bytes or a string such as \"5MB\", got %s", data)
+	}
+	size, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// ParseByteSize reads sizes such as "512", "500KB" or "1.5 MB"
+func ParseByteSize(s string) (ByteSize, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(upper, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return ByteSize(n * float64(multiplier)), nil
+}
+
+func (b ByteSize) String() string {
+	for _, unit := range byteUnits {
+		if int64(b) >= unit.size && unit.size > 1 {
+			return strconv.FormatFloat(float64(b)/float64(unit.size), 'f', 1, 64) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		json string
+		want ByteSize
+	}{
+		{`1024`, 1024},
+		{`"512"`, 512},
+		{`"500KB"`, 500 << 10},
+		{`"1.5 mb"`, 3 << 19},
+		{`"2GB"`, 2 << 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var got ByteSize
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	var b ByteSize
+	if err := json.Unmarshal([]byte(`"lots"`), &b); err == nil {
+		t.Error("expected an invalid size to be rejected")
+	}
+	assertEqual(t, "1.5MB", ByteSize(3<<19).String())
+	assertEqual(t, "12B", ByteSize(12).String())
+}
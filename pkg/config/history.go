@@ -0,0 +1,16 @@
+package config
+
+// History configures remembering the messages written in a repository, so
+// that they can be suggested again
+type History struct {
+	Enabled *bool `json:"enabled"`
+	// Limit is the number of messages kept
+	Limit int `json:"limit"`
+	// GitLog also suggests the messages of commits in the chosen scope
+	GitLog bool `json:"gitLog"`
+}
+
+// IsEnabled reports whether the history is kept, which it is by default
+func (h History) IsEnabled() bool {
+	return h.Enabled == nil || *h.Enabled
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Keys remaps the key bindings used by every form. Each action is written in
+// the config as a single key, such as "ctrl+s", or as a list of keys
+type Keys struct {
+	// Preset is the set of bindings the actions start from: default or vim
+	Preset string `json:"preset"`
+	// Next moves to the next field, and Submit completes the form from its
+	// last field
+	Next   KeyList `json:"next"`
+	Prev   KeyList `json:"prev"`
+	Submit KeyList `json:"submit"`
+	// NewLine and Editor apply to the body
+	NewLine KeyList `json:"newLine"`
+	Editor  KeyList `json:"editor"`
+	// PreviousMessage and NextMessage cycle through the messages written
+	// before
+	PreviousMessage KeyList `json:"previousMessage"`
+	NextMessage     KeyList `json:"nextMessage"`
+	// Toggle switches a yes or no question, and Select ticks an option in a
+	// list such as the co-authors
+	Toggle KeyList `json:"toggle"`
+	Select KeyList `json:"select"`
+	Up     KeyList `json:"up"`
+	Down   KeyList `json:"down"`
+	Filter KeyList `json:"filter"`
+	Quit   KeyList `json:"quit"`
+	// ToggleDiff, ToggleLayout, ScrollUp and ScrollDown control the panes
+	// shown beside the form
+	ToggleDiff   KeyList `json:"toggleDiff"`
+	ToggleLayout KeyList `json:"toggleLayout"`
+	ScrollUp     KeyList `json:"scrollUp"`
+	ScrollDown   KeyList `json:"scrollDown"`
+}
+
+// Bindings returns the keys given for each action, by the action's name in
+// the config. Actions left out of the config are not included
+func (k Keys) Bindings() map[string]KeyList {
+	all := map[string]KeyList{
+		"next":            k.Next,
+		"prev":            k.Prev,
+		"submit":          k.Submit,
+		"newLine":         k.NewLine,
+		"editor":          k.Editor,
+		"previousMessage": k.PreviousMessage,
+		"nextMessage":     k.NextMessage,
+		"toggle":          k.Toggle,
+		"select":          k.Select,
+		"up":              k.Up,
+		"down":            k.Down,
+		"filter":          k.Filter,
+		"quit":            k.Quit,
+		"toggleDiff":      k.ToggleDiff,
+		"toggleLayout":    k.ToggleLayout,
+		"scrollUp":        k.ScrollUp,
+		"scrollDown":      k.ScrollDown,
+	}
+	bindings := map[string]KeyList{}
+	for action, keys := range all {
+		if keys != nil {
+			bindings[action] = keys
+		}
+	}
+	return bindings
+}
+
+// KeyList is the keys bound to an action
+type KeyList []string
+
+func (l *KeyList) UnmarshalJSON(data []byte) error {
+	var key string
+	if err := json.Unmarshal(data, &key); err == nil {
+		*l = KeyList{key}
+		return nil
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return fmt.Errorf("keys must be a key or a list of keys: %w", err)
+	}
+	*l = KeyList(keys)
+	return nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestKeysUnmarshal(t *testing.T) {
+	var keys Keys
+	if err := json.Unmarshal([]byte(`{"preset": "vim", "editor": "ctrl+x", "next": ["enter", "ctrl+n"]}`), &keys); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, "vim", keys.Preset)
+	bindings := keys.Bindings()
+	assertEqual(t, "ctrl+x", strings.Join(bindings["editor"], ","))
+	assertEqual(t, "enter,ctrl+n", strings.Join(bindings["next"], ","))
+	if _, ok := bindings["prev"]; ok {
+		t.Error("expected actions left out of the config to be left out of the bindings")
+	}
+
+	if err := json.Unmarshal([]byte(`{"quit": 3}`), &keys); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+}
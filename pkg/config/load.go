@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/afero"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/lint"
+)
+
+const (
+	DefaultCommitTitleCharLimit      = 48
+	DefaultCommitBodyCharLimit       = 0
+	DefaultCommitBodyLineLength      = 0
+	DefaultScopeDelimiter            = ","
+	DefaultMaxScopes                 = 1
+	DefaultMessageTemplate           = "{{.Type}}{{if .Scope}}({{.Scope}}){{end}}{{if .IsBreakingChange}}!{{end}}: {{.Message}}"
+	DefaultMessageWithTicketTemplate = "{{.TicketNumber}}{{if .Scope}}({{.Scope}}){{end}}{{if .IsBreakingChange}}!{{end}}: <{{.Type}}> {{.Message}}"
+)
+
+// Settings is the fully defaulted configuration used by the rest of meteor
+type Settings struct {
+	MessageTemplate           string
+	MessageWithTicketTemplate string
+	SelectablePrefixes        []Option
+	Prefixes                  []string
+	Coauthors                 []Option
+	Boards                    []Option
+	Scopes                    []Option
+	ScopeStrings              []string
+	CommitTitleCharLimit      int
+	CommitBodyCharLimit       int
+	CommitBodyLineLength      int
+	ShowIntro                 bool
+	ReadContributorsFromGit   bool
+	AllowCustomPrefixes       bool
+	AllowCustomScopes         bool
+	Steps                     []string
+	CustomSteps               CustomSteps
+	Rules                     lint.Rules
+	SpellCheck                SpellCheck
+	// Dictionary is the project word list added to the spell checker
+	Dictionary []string
+	Secrets    SecretScan
+	Guard      Guard
+	Checks     []Check
+	// Sign adds -S to git commit
+	Sign bool
+	// TicketURL is a template for links to tickets, such as
+	// "https://example.atlassian.net/browse/{{.TicketNumber}}"
+	TicketURL string
+	Theme     Theme
+	Keys      Keys
+	// Accessible runs every prompt in huh's line based accessible mode
+	Accessible bool
+	// Locale picks the language of the prompts, and Strings replaces any of
+	// their text by id
+	Locale  string
+	Strings Strings
+	History History
+	// Behaviours changes the wizard for particular types and scopes
+	Behaviours Behaviours
+	// CustomScopePattern is matched by every scope typed in that isn't
+	// configured, or is nil to allow any
+	CustomScopePattern *regexp.Regexp
+	// ScopeDelimiter separates the scopes of a commit, of which there can be
+	// up to MaxScopes
+	ScopeDelimiter string
+	MaxScopes      int
+}
+
+// Commit returns the settings needed to render and parse commit messages
+func (s Settings) Commit() commit.Config {
+	return commit.Config{
+		MessageTemplate:           s.MessageTemplate,
+		MessageWithTicketTemplate: s.MessageWithTicketTemplate,
+		BodyLineLength:            s.CommitBodyLineLength,
+		ScopeDelimiter:            s.ScopeDelimiter,
+		MaxScopes:                 s.MaxScopes,
+	}
+}
+
+// Default returns the settings used when there is no config file
+func Default() Settings {
+	return Settings{
+		MessageTemplate:           DefaultMessageTemplate,
+		MessageWithTicketTemplate: DefaultMessageWithTicketTemplate,
+		SelectablePrefixes:        DefaultSelectablePrefixes,
+		Prefixes:                  DefaultPrefixes,
+		CommitTitleCharLimit:      DefaultCommitTitleCharLimit,
+		CommitBodyCharLimit:       DefaultCommitBodyCharLimit,
+		CommitBodyLineLength:      DefaultCommitBodyLineLength,
+		ShowIntro:                 true,
+		ReadContributorsFromGit:   false,
+		AllowCustomPrefixes:       false,
+		ScopeDelimiter:            DefaultScopeDelimiter,
+		MaxScopes:                 DefaultMaxScopes,
+	}
+}
+
+// Load finds the config file from the current directory or any parent and
+// returns its settings with every missing option defaulted
+func Load(fs afero.Fs) (Settings, error) {
+	filePath, err := FindConfigFile(fs, os.Getwd, os.UserHomeDir)
+	if err != nil {
+		log.Debug("Error finding config file", "error", err)
+		return Default(), nil
+	}
+
+	log.Debug("found config file", "path", filePath)
+
+	c := New()
+
+	err = c.LoadFile(filePath)
+	if err != nil {
+		return Settings{
+			MessageTemplate:           DefaultMessageTemplate,
+			MessageWithTicketTemplate: DefaultMessageWithTicketTemplate,
+			CommitTitleCharLimit:      DefaultCommitTitleCharLimit,
+			CommitBodyCharLimit:       DefaultCommitBodyCharLimit,
+			CommitBodyLineLength:      DefaultCommitBodyLineLength,
+			ShowIntro:                 true,
+			ReadContributorsFromGit:   false,
+			AllowCustomPrefixes:       false,
+			ScopeDelimiter:            DefaultScopeDelimiter,
+			MaxScopes:                 DefaultMaxScopes,
+		}, fmt.Errorf("error parsing config file: %w", err)
+	}
+
+	return c.Settings(), nil
+}
+
+// Settings applies the defaults to any options missing from the config
+func (c *Config) Settings() Settings {
+	if c.ShowIntro == nil {
+		showIntro := true
+		c.ShowIntro = &showIntro
+	}
+
+	if c.CommitTitleCharLimit == nil || *c.CommitTitleCharLimit < DefaultCommitTitleCharLimit {
+		commitTitleCharLimit := DefaultCommitTitleCharLimit
+		c.CommitTitleCharLimit = &commitTitleCharLimit
+	}
+
+	if c.CommitBodyCharLimit == nil || *c.CommitBodyCharLimit < DefaultCommitBodyCharLimit {
+		commitBodyCharLimit := DefaultCommitBodyCharLimit
+		c.CommitBodyCharLimit = &commitBodyCharLimit
+	}
+
+	if c.CommitBodyLineLength == nil || *c.CommitBodyLineLength < commit.MinimumBodyLineLength {
+		commitBodyLineLength := DefaultCommitBodyLineLength
+		c.CommitBodyLineLength = &commitBodyLineLength
+	}
+
+	if c.ReadContributorsFromGit == nil {
+		read := false
+		c.ReadContributorsFromGit = &read
+	}
+
+	if c.AllowCustomPrefixes == nil {
+		allowCustomPrefixes := false
+		c.AllowCustomPrefixes = &allowCustomPrefixes
+	}
+
+	if c.AllowCustomScopes == nil {
+		allowCustomScopes := false
+		c.AllowCustomScopes = &allowCustomScopes
+	}
+
+	if c.ScopeDelimiter == nil || *c.ScopeDelimiter == "" {
+		scopeDelimiter := DefaultScopeDelimiter
+		c.ScopeDelimiter = &scopeDelimiter
+	}
+
+	if c.MaxScopes == nil || *c.MaxScopes < DefaultMaxScopes {
+		maxScopes := DefaultMaxScopes
+		c.MaxScopes = &maxScopes
+	}
+
+	var customScopePattern *regexp.Regexp
+	if c.CustomScopePattern != nil {
+		pattern, err := regexp.Compile("^(?:" + *c.CustomScopePattern + ")$")
+		if err != nil {
+			log.Error("Ignoring invalid custom scope pattern", "error", err)
+		} else {
+			customScopePattern = pattern
+		}
+	}
+
+	var err error
+	messageTemplate := DefaultMessageTemplate
+	if c.MessageTemplate != nil {
+		messageTemplate, err = ConvertTemplate(*c.MessageTemplate)
+		if err != nil {
+			log.Error("Error converting message template", "error", err)
+			messageTemplate = DefaultMessageTemplate
+		}
+	}
+	c.MessageTemplate = &messageTemplate
+
+	messageWithTicketTemplate := DefaultMessageWithTicketTemplate
+	if c.MessageWithTicketTemplate != nil {
+		messageWithTicketTemplate, err = ConvertTemplate(*c.MessageWithTicketTemplate)
+		if err != nil {
+			log.Error("Error converting message with ticket template", "error", err)
+			messageWithTicketTemplate = DefaultMessageWithTicketTemplate
+		}
+	}
+	c.MessageWithTicketTemplate = &messageWithTicketTemplate
+
+	rules := lint.Rules{}
+	for name, rule := range c.Rules {
+		if err := (lint.Rules{name: rule}).Validate(); err != nil {
+			log.Error("Ignoring invalid rule", "error", err)
+			continue
+		}
+		rules[name] = rule
+	}
+
+	return Settings{
+		MessageTemplate:           messageTemplate,
+		MessageWithTicketTemplate: messageWithTicketTemplate,
+		SelectablePrefixes:        c.Prefixes.Options(),
+		Prefixes:                  c.Prefixes.Strings(),
+		Coauthors:                 c.Coauthors.Options(),
+		Boards:                    c.Boards.Options(),
+		Scopes:                    c.Scopes.Options(),
+		ScopeStrings:              c.Scopes.Strings(),
+		CommitTitleCharLimit:      *c.CommitTitleCharLimit,
+		CommitBodyCharLimit:       *c.CommitBodyCharLimit,
+		CommitBodyLineLength:      *c.CommitBodyLineLength,
+		ShowIntro:                 *c.ShowIntro,
+		ReadContributorsFromGit:   *c.ReadContributorsFromGit,
+		AllowCustomPrefixes:       *c.AllowCustomPrefixes,
+		AllowCustomScopes:         *c.AllowCustomScopes,
+		CustomScopePattern:        customScopePattern,
+		ScopeDelimiter:            *c.ScopeDelimiter,
+		MaxScopes:                 *c.MaxScopes,
+		Steps:                     c.Steps,
+		CustomSteps:               c.CustomSteps,
+		Rules:                     rules,
+		SpellCheck:                c.SpellCheck,
+		Dictionary:                c.Dictionary,
+		Secrets:                   c.Secrets,
+		Guard:                     c.Guard,
+		Checks:                    c.Checks,
+		Sign:                      c.Sign,
+		TicketURL:                 c.TicketURL,
+		Theme:                     c.Theme,
+		Keys:                      c.Keys,
+		Accessible:                c.Accessible,
+		Locale:                    c.Locale,
+		Strings:                   c.Strings,
+		History:                   c.History,
+		Behaviours:                Behaviours{Types: c.Prefixes.Behaviours(), Scopes: c.Scopes.Behaviours()},
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestConfig_Settings(t *testing.T) {
+	t.Run("empty config is defaulted", func(t *testing.T) {
+		got := New().Settings()
+		assertEqual(t, DefaultMessageTemplate, got.MessageTemplate)
+		assertEqual(t, DefaultMessageWithTicketTemplate, got.MessageWithTicketTemplate)
+		if got.CommitTitleCharLimit != DefaultCommitTitleCharLimit {
+			t.Errorf("CommitTitleCharLimit = %d, want %d", got.CommitTitleCharLimit, DefaultCommitTitleCharLimit)
+		}
+		if !got.ShowIntro {
+			t.Error("ShowIntro = false, want true")
+		}
+		if len(got.Prefixes) != len(DefaultPrefixes) {
+			t.Errorf("Prefixes has %d items, want %d", len(got.Prefixes), len(DefaultPrefixes))
+		}
+	})
+	t.Run("line length under the minimum is disabled", func(t *testing.T) {
+		lineLength := 10
+		c := &Config{CommitBodyLineLength: &lineLength}
+		if got := c.Settings().CommitBodyLineLength; got != DefaultCommitBodyLineLength {
+			t.Errorf("CommitBodyLineLength = %d, want %d", got, DefaultCommitBodyLineLength)
+		}
+	})
+	t.Run("templates are converted", func(t *testing.T) {
+		tmpl := "@type: @message"
+		c := &Config{MessageTemplate: &tmpl}
+		assertEqual(t, "{{.Type}}{{if .IsBreakingChange}}!{{end}}: {{.Message}}", c.Settings().MessageTemplate)
+	})
+	t.Run("invalid templates fall back to the default", func(t *testing.T) {
+		tmpl := "@scope"
+		c := &Config{MessageTemplate: &tmpl}
+		assertEqual(t, DefaultMessageTemplate, c.Settings().MessageTemplate)
+	})
+	t.Run("invalid rules are dropped", func(t *testing.T) {
+		c := New()
+		if err := json.Unmarshal([]byte(`{"rules": {"subject-full-stop": [2, "never"], "subject-colour": [2, "always"]}}`), c); err != nil {
+			t.Fatal(err)
+		}
+		got := c.Settings().Rules
+		if _, ok := got["subject-full-stop"]; !ok || len(got) != 1 {
+			t.Errorf("Rules = %v, want only subject-full-stop", got)
+		}
+	})
+	t.Run("scope settings are defaulted and compiled", func(t *testing.T) {
+		got := New().Settings()
+		if got.ScopeDelimiter != DefaultScopeDelimiter || got.MaxScopes != DefaultMaxScopes || got.CustomScopePattern != nil {
+			t.Errorf("scope settings = %q, %d, %v, want the defaults", got.ScopeDelimiter, got.MaxScopes, got.CustomScopePattern)
+		}
+
+		c := New()
+		if err := json.Unmarshal([]byte(`{"customScopePattern": "[a-z]+", "scopeDelimiter": "+", "maxScopes": 3}`), c); err != nil {
+			t.Fatal(err)
+		}
+		got = c.Settings()
+		if got.ScopeDelimiter != "+" || got.MaxScopes != 3 {
+			t.Errorf("scope settings = %q, %d, want \"+\", 3", got.ScopeDelimiter, got.MaxScopes)
+		}
+		if !got.CustomScopePattern.MatchString("api") || got.CustomScopePattern.MatchString("api-v2") {
+			t.Errorf("CustomScopePattern = %v, want it to match whole scopes", got.CustomScopePattern)
+		}
+	})
+}
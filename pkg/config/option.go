@@ -0,0 +1,12 @@
+package config
+
+// Option is a choice offered by the wizard, shown as its label
+type Option struct {
+	Label string
+	Value string
+}
+
+// NewOption returns an option with the given label and value
+func NewOption(label string, value string) Option {
+	return Option{Label: label, Value: value}
+}
@@ -1,14 +1,15 @@
 package config
 
-import (
-	"fmt"
-
-	"github.com/charmbracelet/huh"
-)
+import "fmt"
 
 type Prefix struct {
 	T string `json:"type"`
 	D string `json:"description"`
+	Behaviour
+	// DefaultScope is chosen for the commit when it has no scope yet, and
+	// AllowedScopes limits the scopes that can be picked for this type
+	DefaultScope  string   `json:"defaultScope"`
+	AllowedScopes []string `json:"allowedScopes"`
 }
 
 type Prefixes []Prefix
@@ -27,30 +28,30 @@ var DefaultPrefixes = []string{
 	"test",
 }
 
-var DefaultSelectablePrefixes = []huh.Option[string]{
-	huh.NewOption("feat - a new feature", "feat"),
-	huh.NewOption("fix - a bug fix", "fix"),
-	huh.NewOption("build - changes that affect the build system or external dependencies", "build"),
-	huh.NewOption("chore - changes to the build process or auxiliary tools and libraries", "chore"),
-	huh.NewOption("ci - changes to our CI configuration files and scripts", "ci"),
-	huh.NewOption("docs - documentation only changes", "docs"),
-	huh.NewOption("perf - a code change that improves performance", "perf"),
-	huh.NewOption("refactor - a code change that neither fixes a bug nor adds a feature", "refactor"),
-	huh.NewOption("revert - reverts a previous commit", "revert"),
-	huh.NewOption("style - changes that do not affect the meaning of the code", "style"),
-	huh.NewOption("test - adding missing tests or correcting existing tests", "test"),
+var DefaultSelectablePrefixes = []Option{
+	NewOption("feat - a new feature", "feat"),
+	NewOption("fix - a bug fix", "fix"),
+	NewOption("build - changes that affect the build system or external dependencies", "build"),
+	NewOption("chore - changes to the build process or auxiliary tools and libraries", "chore"),
+	NewOption("ci - changes to our CI configuration files and scripts", "ci"),
+	NewOption("docs - documentation only changes", "docs"),
+	NewOption("perf - a code change that improves performance", "perf"),
+	NewOption("refactor - a code change that neither fixes a bug nor adds a feature", "refactor"),
+	NewOption("revert - reverts a previous commit", "revert"),
+	NewOption("style - changes that do not affect the meaning of the code", "style"),
+	NewOption("test - adding missing tests or correcting existing tests", "test"),
 }
 
-func (p *Prefixes) Options() []huh.Option[string] {
+func (p *Prefixes) Options() []Option {
 	prefixes := []Prefix(*p)
 
 	if len(prefixes) == 0 {
 		return DefaultSelectablePrefixes
 	}
-	var items []huh.Option[string]
+	var items []Option
 	for _, prefix := range prefixes {
 		desc := fmt.Sprintf("%s - %s", prefix.T, prefix.D)
-		items = append(items, huh.NewOption(desc, prefix.T))
+		items = append(items, NewOption(desc, prefix.T))
 	}
 	return items
 }
@@ -67,3 +68,12 @@ func (p *Prefixes) Strings() []string {
 	}
 	return items
 }
+
+// Behaviours returns the configured prefixes by type
+func (p *Prefixes) Behaviours() map[string]Prefix {
+	items := map[string]Prefix{}
+	for _, prefix := range *p {
+		items[prefix.T] = prefix
+	}
+	return items
+}
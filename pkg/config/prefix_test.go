@@ -77,8 +77,8 @@ func TestPrefixes_Options_Format(t *testing.T) {
 	expectedKey := "feat - a new feature"
 	expectedValue := "feat"
 
-	if got[0].Key != expectedKey {
-		t.Errorf("Options() key = %q, want %q", got[0].Key, expectedKey)
+	if got[0].Label != expectedKey {
+		t.Errorf("Options() label = %q, want %q", got[0].Label, expectedKey)
 	}
 
 	if got[0].Value != expectedValue {
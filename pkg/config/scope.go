@@ -1,37 +1,80 @@
 package config
 
-import "github.com/charmbracelet/huh"
+import "fmt"
 
 type Scope struct {
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Behaviour
+	// Scopes are nested under this one, and are written as "parent/child"
+	Scopes Scopes `json:"scopes"`
 }
 
 type Scopes []Scope
 
-func (s *Scopes) Options() []huh.Option[string] {
-	scopes := []Scope(*s)
-	if len(scopes) == 0 {
+// Options returns every scope, nested ones included, after a "none" option.
+// Nested scopes are labelled as a tree below their parent
+func (s *Scopes) Options() []Option {
+	if len(*s) == 0 {
 		return nil
 	}
-	items := []huh.Option[string]{}
-	for _, scope := range scopes {
-		items = append(items, huh.NewOption(scope.Name, scope.Name))
+	items := []Option{NewOption("none", "")}
+	return append(items, s.options("", "")...)
+}
+
+// options returns the scopes under parent, with the labels of nested scopes
+// drawn as branches after the indent
+func (s *Scopes) options(parent string, indent string) []Option {
+	items := []Option{}
+	for i, scope := range *s {
+		name := scopeName(parent, scope.Name)
+		label, nested := scope.Name, ""
+		if parent != "" {
+			branch, rest := "├─ ", "│  "
+			if i == len(*s)-1 {
+				branch, rest = "└─ ", "   "
+			}
+			label, nested = indent+branch+scope.Name, indent+rest
+		}
+		if scope.Description != "" {
+			label = fmt.Sprintf("%s - %s", label, scope.Description)
+		}
+		items = append(items, NewOption(label, name))
+		items = append(items, scope.Scopes.options(name, nested)...)
 	}
-	items = append(items, huh.Option[string]{})
-	copy(items[1:], items)
-	items[0] = huh.NewOption("none", "")
 	return items
 }
 
+// Strings returns the full names of every scope, nested ones included
 func (s *Scopes) Strings() []string {
-	scopes := []Scope(*s)
+	var items []string
+	s.walk("", func(name string, _ Scope) {
+		items = append(items, name)
+	})
+	return items
+}
 
-	if len(scopes) == 0 {
-		return nil
+// Behaviours returns the configured scopes by their full names
+func (s *Scopes) Behaviours() map[string]Scope {
+	items := map[string]Scope{}
+	s.walk("", func(name string, scope Scope) {
+		items[name] = scope
+	})
+	return items
+}
+
+// walk calls fn for every scope under parent, parents before their children
+func (s *Scopes) walk(parent string, fn func(name string, scope Scope)) {
+	for _, scope := range *s {
+		name := scopeName(parent, scope.Name)
+		fn(name, scope)
+		scope.Scopes.walk(name, fn)
 	}
-	var items []string
-	for _, scope := range scopes {
-		items = append(items, scope.Name)
+}
+
+func scopeName(parent string, name string) string {
+	if parent == "" {
+		return name
 	}
-	return items
+	return parent + "/" + name
 }
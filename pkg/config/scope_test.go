@@ -66,8 +66,8 @@ func TestScopes_Options(t *testing.T) {
 				t.Errorf("Options()[0].Value = %q, want %q", got[0].Value, tt.wantFirst)
 			}
 
-			if got[0].Key != "none" {
-				t.Errorf("Options()[0].Key = %q, want %q", got[0].Key, "none")
+			if got[0].Label != "none" {
+				t.Errorf("Options()[0].Label = %q, want %q", got[0].Label, "none")
 			}
 
 			// Check second option if exists
@@ -99,14 +99,14 @@ func TestScopes_Options_OrderPreserved(t *testing.T) {
 	}
 
 	// Verify "none" is first
-	if got[0].Key != "none" {
-		t.Errorf("Options()[0].Key = %q, want %q", got[0].Key, "none")
+	if got[0].Label != "none" {
+		t.Errorf("Options()[0].Label = %q, want %q", got[0].Label, "none")
 	}
 
 	// Verify other keys match their values
 	for i := 1; i < len(got); i++ {
-		if got[i].Key != got[i].Value {
-			t.Errorf("Options()[%d].Key = %q, want %q", i, got[i].Key, got[i].Value)
+		if got[i].Label != got[i].Value {
+			t.Errorf("Options()[%d].Label = %q, want %q", i, got[i].Label, got[i].Value)
 		}
 	}
 }
@@ -213,3 +213,36 @@ func TestScopes_Strings_OrderPreserved(t *testing.T) {
 		}
 	}
 }
+
+func TestScopes_Nested(t *testing.T) {
+	scopes := Scopes{
+		{Name: "api", Description: "the public API", Scopes: Scopes{
+			{Name: "auth", Scopes: Scopes{{Name: "tokens"}}},
+			{Name: "users", Description: "accounts"},
+		}},
+		{Name: "web"},
+	}
+
+	wantValues := []string{"", "api", "api/auth", "api/auth/tokens", "api/users", "web"}
+	wantKeys := []string{"none", "api - the public API", "├─ auth", "│  └─ tokens", "└─ users - accounts", "web"}
+	got := scopes.Options()
+	if len(got) != len(wantValues) {
+		t.Fatalf("Options() returned %d items, want %d", len(got), len(wantValues))
+	}
+	for i := range got {
+		assertEqual(t, wantValues[i], got[i].Value)
+		assertEqual(t, wantKeys[i], got[i].Label)
+	}
+
+	strings := scopes.Strings()
+	if len(strings) != len(wantValues)-1 {
+		t.Fatalf("Strings() returned %d items, want %d", len(strings), len(wantValues)-1)
+	}
+	for i, v := range strings {
+		assertEqual(t, wantValues[i+1], v)
+	}
+
+	if _, ok := scopes.Behaviours()["api/auth/tokens"]; !ok {
+		t.Error("Behaviours() is missing the nested api/auth/tokens scope")
+	}
+}
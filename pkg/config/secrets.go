@@ -0,0 +1,26 @@
+package config
+
+// SecretScan configures scanning the staged changes for secrets before
+// committing
+type SecretScan struct {
+	Enabled *bool `json:"enabled"`
+	// Allow lists regular expressions for matches that are not secrets
+	Allow []string `json:"allow"`
+	// AllowPaths lists globs for files that are not scanned
+	AllowPaths []string        `json:"allowPaths"`
+	Patterns   []SecretPattern `json:"patterns"`
+	// Entropy is the threshold for reporting random looking strings, or 0 to
+	// turn the check off
+	Entropy *float64 `json:"entropy"`
+}
+
+// SecretPattern is a custom regular expression to scan for
+type SecretPattern struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+// IsEnabled reports whether scanning is on, which it is by default
+func (s SecretScan) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
@@ -0,0 +1,11 @@
+package config
+
+// SpellCheck configures the spell checker for the message and body
+type SpellCheck struct {
+	Enabled bool `json:"enabled"`
+	// Block stops the message form from accepting misspelled words
+	Block bool `json:"block"`
+	// Dictionaries are extra Hunspell dictionaries, given as paths without
+	// the .aff and .dic extensions
+	Dictionaries []string `json:"dictionaries"`
+}
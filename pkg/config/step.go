@@ -0,0 +1,24 @@
+package config
+
+// DefaultSteps is the order the wizard runs its built-in steps in when no
+// steps are configured
+var DefaultSteps = []string{
+	"intro",
+	"board",
+	"ticket",
+	"type",
+	"coauthors",
+	"message",
+	"confirm",
+}
+
+// CustomStep is a user defined prompt added to the wizard. Its answer is
+// appended to the commit body as a trailer
+type CustomStep struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Trailer     string `json:"trailer"`
+}
+
+type CustomSteps []CustomStep
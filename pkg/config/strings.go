@@ -0,0 +1,5 @@
+package config
+
+// Strings replaces the text of prompts, keyed by the id of the text such as
+// "confirm.title"
+type Strings map[string]string
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Theme picks the colours of the forms and of meteor's messages. It is
+// written in the config as the name of a base theme, or as an object with a
+// base theme and colours to override
+type Theme struct {
+	// Base is one of huh's themes: charm, dracula, base16 or catppuccin
+	Base        string `json:"base"`
+	Title       Color  `json:"title"`
+	Description Color  `json:"description"`
+	// Selector colours the cursor and prompts, and Selected the chosen
+	// options
+	Selector Color `json:"selector"`
+	Selected Color `json:"selected"`
+	// Button is the background of the focused button
+	Button Color `json:"button"`
+	// Error colours validation errors and failure messages, Warning the hints
+	// that follow them, Info the commands they suggest and Success the
+	// messages after a command worked
+	Error   Color `json:"error"`
+	Warning Color `json:"warning"`
+	Info    Color `json:"info"`
+	Success Color `json:"success"`
+}
+
+func (t *Theme) UnmarshalJSON(data []byte) error {
+	var base string
+	if err := json.Unmarshal(data, &base); err == nil {
+		*t = Theme{Base: base}
+		return nil
+	}
+	type theme Theme
+	var v theme
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("theme must be a name or an object: %w", err)
+	}
+	*t = Theme(v)
+	return nil
+}
+
+// Color is a colour for light and dark terminals, written in the config as
+// a single colour such as "#FF4672" or "204", or as {"light": ..., "dark": ...}
+type Color struct {
+	Light string `json:"light"`
+	Dark  string `json:"dark"`
+}
+
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*c = Color{Light: s, Dark: s}
+		return nil
+	}
+	type color Color
+	var v color
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("colour must be a string or an object with light and dark colours: %w", err)
+	}
+	*c = Color(v)
+	return nil
+}
+
+// IsSet reports whether a colour was given
+func (c Color) IsSet() bool {
+	return c.Light != "" || c.Dark != ""
+}
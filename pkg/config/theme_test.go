@@ -0,0 +1,37 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestThemeUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Theme
+	}{
+		{"name", `"dracula"`, Theme{Base: "dracula"}},
+		{"object", `{"base": "charm", "title": "#7571F9", "error": {"light": "160", "dark": "204"}}`, Theme{
+			Base:  "charm",
+			Title: Color{Light: "#7571F9", Dark: "#7571F9"},
+			Error: Color{Light: "160", Dark: "204"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Theme
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	var theme Theme
+	if err := json.Unmarshal([]byte(`{"title": 12}`), &theme); err == nil {
+		t.Error("expected an invalid colour to be rejected")
+	}
+}
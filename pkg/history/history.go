@@ -0,0 +1,81 @@
+// Package history keeps the commit messages written with meteor in a
+// repository, so that they can be suggested again.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultLimit is the number of messages kept when the config doesn't say
+const DefaultLimit = 100
+
+// Entry is a message written for a commit
+type Entry struct {
+	Type    string    `json:"type"`
+	Scope   string    `json:"scope"`
+	Message string    `json:"message"`
+	Body    string    `json:"body"`
+	Time    time.Time `json:"time"`
+}
+
+// Path returns where the history of the repository with the given git
+// directory is kept
+func Path(gitDir string) string {
+	return filepath.Join(gitDir, "meteor", "history.json")
+}
+
+// Load reads the history, newest first. A missing file is an empty history
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the message history: %w", err)
+	}
+	entries := []Entry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("could not parse the message history: %w", err)
+	}
+	return entries, nil
+}
+
+// Save writes the history, creating its directory if needed
+func Save(path string, entries []Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not save the message history: %w", err)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("could not save the message history: %w", err)
+	}
+	return nil
+}
+
+// Add puts the entry at the front of the history, dropping any older entry
+// with the same message and scope, and keeps at most limit entries
+func Add(entries []Entry, e Entry, limit int) []Entry {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	added := []Entry{e}
+	for _, old := range entries {
+		if old.Message == e.Message && old.Scope == e.Scope {
+			continue
+		}
+		added = append(added, old)
+	}
+	if len(added) > limit {
+		added = added[:limit]
+	}
+	return added
+}
@@ -0,0 +1,57 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	entries := []Entry{
+		{Scope: "deps", Message: "bump foo"},
+		{Scope: "api", Message: "add users"},
+		{Scope: "web", Message: "bump foo"},
+	}
+	tests := []struct {
+		name  string
+		entry Entry
+		limit int
+		want  []string
+	}{
+		{"new message", Entry{Scope: "api", Message: "add teams"}, 0, []string{"add teams", "bump foo", "add users", "bump foo"}},
+		{"repeated message moves to the front", Entry{Scope: "api", Message: "add users"}, 0, []string{"add users", "bump foo", "bump foo"}},
+		{"same message in another scope is kept", Entry{Scope: "deps", Message: "bump foo"}, 0, []string{"bump foo", "add users", "bump foo"}},
+		{"limit", Entry{Scope: "api", Message: "add teams"}, 2, []string{"add teams", "bump foo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, e := range Add(entries, tt.entry, tt.limit) {
+				got = append(got, e.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := Path(filepath.Join(t.TempDir(), ".git"))
+	entries, err := Load(path)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty history before saving, got %v, %v", entries, err)
+	}
+
+	want := []Entry{{Type: "chore", Scope: "deps", Message: "bump foo", Body: "from 1 to 2"}}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,219 @@
+// Package lint checks commit messages against commitlint style rules.
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+)
+
+// Severity is how a broken rule is reported
+type Severity int
+
+const (
+	Off Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "off"
+	}
+}
+
+const (
+	Always = "always"
+	Never  = "never"
+)
+
+// Rule configures a single check. In the config file it is written the same
+// way as in commitlint, as [severity, when, value], for example
+// [2, "never", "."]. The severity can also be "off", "warning" or "error"
+type Rule struct {
+	Severity Severity
+	When     string
+	Value    json.RawMessage
+}
+
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	var parts []json.RawMessage
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("a rule must be an array of [severity, when, value]: %w", err)
+	}
+	if len(parts) == 0 || len(parts) > 3 {
+		return errors.New("a rule must be an array of [severity, when, value]")
+	}
+
+	severity, err := parseSeverity(parts[0])
+	if err != nil {
+		return err
+	}
+	*r = Rule{Severity: severity, When: Always}
+
+	if len(parts) > 1 {
+		if err := json.Unmarshal(parts[1], &r.When); err != nil || (r.When != Always && r.When != Never) {
+			return fmt.Errorf("when must be %q or %q, got %s", Always, Never, parts[1])
+		}
+	}
+	if len(parts) > 2 {
+		r.Value = parts[2]
+	}
+	return nil
+}
+
+func parseSeverity(data json.RawMessage) (Severity, error) {
+	var level int
+	if err := json.Unmarshal(data, &level); err == nil && level >= int(Off) && level <= int(Error) {
+		return Severity(level), nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		for _, s := range []Severity{Off, Warning, Error} {
+			if name == s.String() {
+				return s, nil
+			}
+		}
+	}
+	return Off, fmt.Errorf("severity must be 0, 1, 2, \"off\", \"warning\" or \"error\", got %s", data)
+}
+
+// Rules maps rule names, such as "subject-full-stop", to their settings
+type Rules map[string]Rule
+
+// Validate returns an error for every unknown rule or rule with a value it
+// cannot use
+func (r Rules) Validate() error {
+	var errs []error
+	for _, name := range r.names() {
+		def, ok := definitions[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown rule %q", name))
+			continue
+		}
+		if _, err := def.check(message{}, r[name].Value); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r Rules) names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Problem is a rule that the message breaks
+type Problem struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s [%s]", p.Severity, p.Message, p.Rule)
+}
+
+// Field returns the part of the message the rule applies to, such as
+// "subject" or "body"
+func (p Problem) Field() string {
+	field, _, _ := strings.Cut(p.Rule, "-")
+	return field
+}
+
+// HasErrors reports whether any of the problems is an error
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint checks the full commit message against the rules. The subject is
+// parsed with the message templates to find its type, scope and message.
+// Errors are listed before warnings
+func Lint(rules Rules, c commit.Config, text string) []Problem {
+	m := parse(c, text)
+	problems := []Problem{}
+	for _, name := range rules.names() {
+		rule := rules[name]
+		def, ok := definitions[name]
+		if !ok || rule.Severity == Off || def.skipEmpty && def.part(m) == "" {
+			continue
+		}
+		ok, err := def.check(m, rule.Value)
+		if err != nil {
+			continue
+		}
+		never := rule.When == Never && !def.ignoresWhen
+		if ok != never {
+			continue
+		}
+		must := "must"
+		if never {
+			must = "must not"
+		}
+		problems = append(problems, Problem{
+			Rule:     name,
+			Severity: rule.Severity,
+			Message:  fmt.Sprintf("%s %s %s", def.field, must, def.describe(rule.Value)),
+		})
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Severity > problems[j].Severity
+	})
+	return problems
+}
+
+// message is a commit message split into the parts the rules check
+type message struct {
+	header  string
+	subject string
+	typ     string
+	scope   string
+	body    string
+	footer  string
+	lines   []string
+	// footerStart is the index of the first footer line in lines
+	footerStart int
+}
+
+func parse(c commit.Config, text string) message {
+	text = strings.TrimRight(text, "\n")
+	lines := strings.Split(text, "\n")
+	cm, err := commit.Parse(c, text)
+	m := message{
+		header:      lines[0],
+		subject:     cm.Message,
+		typ:         cm.Type,
+		scope:       cm.Scope,
+		body:        cm.Body,
+		lines:       lines,
+		footerStart: len(lines),
+	}
+	if errors.Is(err, commit.ErrNoMatch) {
+		m.subject = strings.TrimSpace(lines[0])
+	}
+
+	footer := commit.Body(commit.Config{}, commit.Commit{Trailers: cm.Trailers, Coauthors: cm.Coauthors})
+	if footer != "" {
+		m.footer = footer
+		m.footerStart = len(lines) - len(strings.Split(footer, "\n"))
+	}
+	return m
+}
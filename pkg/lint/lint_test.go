@@ -0,0 +1,183 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/lint"
+)
+
+var testConfig = commit.Config{
+	MessageTemplate:           config.DefaultMessageTemplate,
+	MessageWithTicketTemplate: config.DefaultMessageWithTicketTemplate,
+}
+
+func rules(t *testing.T, text string) lint.Rules {
+	t.Helper()
+	var r lint.Rules
+	if err := json.Unmarshal([]byte(text), &r); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		message string
+		want    []string
+	}{
+		{
+			name:    "passing message",
+			rules:   `{"subject-case": [2, "never", ["sentence-case", "upper-case"]], "subject-full-stop": [2, "never"], "header-max-length": [2, "always", 72], "type-enum": [2, "always", ["feat", "fix"]]}`,
+			message: "feat(api): add login\n\nSome body",
+			want:    []string{},
+		},
+		{
+			name:    "subject case and full stop",
+			rules:   `{"subject-case": [2, "never", ["sentence-case", "upper-case"]], "subject-full-stop": [1, "never", "."]}`,
+			message: "feat: Add login.",
+			want: []string{
+				"error: subject must not be one of [sentence-case, upper-case] [subject-case]",
+				"warning: subject must not end with . [subject-full-stop]",
+			},
+		},
+		{
+			name:    "empty subject",
+			rules:   `{"subject-empty": [2, "never"], "subject-case": [2, "always", "lower-case"]}`,
+			message: "feat: ",
+			want:    []string{"error: subject must not be empty [subject-empty]"},
+		},
+		{
+			name:    "header length ignores never",
+			rules:   `{"header-max-length": ["error", "never", 10]}`,
+			message: "feat: add login",
+			want:    []string{"error: header must not be longer than 10 characters [header-max-length]"},
+		},
+		{
+			name:    "type and scope enums",
+			rules:   `{"type-enum": [2, "always", ["feat", "fix"]], "scope-enum": [2, "always", ["api"]]}`,
+			message: "chore(web): tidy",
+			want: []string{
+				"error: scope must be api [scope-enum]",
+				"error: type must be one of [feat, fix] [type-enum]",
+			},
+		},
+		{
+			name:    "scope enum skips missing scope",
+			rules:   `{"scope-enum": [2, "always", ["api"]]}`,
+			message: "chore: tidy",
+			want:    []string{},
+		},
+		{
+			name:    "body leading blank",
+			rules:   `{"body-leading-blank": [1, "always"]}`,
+			message: "feat: add login\nbody right after",
+			want:    []string{"warning: body must start with a blank line [body-leading-blank]"},
+		},
+		{
+			name:    "footer line length",
+			rules:   `{"footer-max-line-length": [2, "always", 30], "footer-leading-blank": [2, "always"]}`,
+			message: "feat: add login\n\nbody\n\nCo-authored-by: Someone With A Long Name <someone@example.com>",
+			want:    []string{"error: footer must not have lines longer than 30 characters [footer-max-line-length]"},
+		},
+		{
+			name:    "imperative mood",
+			rules:   `{"subject-imperative": [2, "always"]}`,
+			message: "fix: fixed the login page",
+			want:    []string{`error: subject must use the imperative mood, such as "add" rather than "added" or "adds" [subject-imperative]`},
+		},
+		{
+			name:    "imperative mood third person",
+			rules:   `{"subject-imperative": [2, "always"]}`,
+			message: "fix: fixes the login page",
+			want:    []string{`error: subject must use the imperative mood, such as "add" rather than "added" or "adds" [subject-imperative]`},
+		},
+		{
+			name:    "imperative mood passes",
+			rules:   `{"subject-imperative": [2, "always"]}`,
+			message: "fix: address the login page process",
+			want:    []string{},
+		},
+		{
+			name:    "repeated type",
+			rules:   `{"subject-type-repeat": [2, "never"]}`,
+			message: "fix: fix the login page",
+			want:    []string{"error: subject must not repeat the type [subject-type-repeat]"},
+		},
+		{
+			name:    "ticket template",
+			rules:   `{"type-enum": [2, "always", ["feat"]]}`,
+			message: "ABC-123: <fix> the login page",
+			want:    []string{"error: type must be feat [type-enum]"},
+		},
+		{
+			name:    "off rules are skipped",
+			rules:   `{"subject-full-stop": [0, "never"]}`,
+			message: "feat: add login.",
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, p := range lint.Lint(rules(t, tt.rules), testConfig, tt.message) {
+				got = append(got, p.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+		want  string
+	}{
+		{"unknown rule", `{"subject-colour": [2, "always"]}`, `unknown rule "subject-colour"`},
+		{"missing value", `{"type-enum": [2, "always"]}`, `rule "type-enum": value must be a string or a list of strings`},
+		{"bad length", `{"header-max-length": [2, "always", "long"]}`, `rule "header-max-length": value must be a positive number, got "long"`},
+		{"unknown case", `{"subject-case": [2, "always", "title-case"]}`, `rule "subject-case": unknown case "title-case"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r lint.Rules
+			if err := json.Unmarshal([]byte(tt.rules), &r); err != nil {
+				t.Fatal(err)
+			}
+			err := r.Validate()
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleUnmarshal(t *testing.T) {
+	for _, bad := range []string{`2`, `[]`, `[3]`, `["loud"]`, `[2, "sometimes"]`, `[2, "always", 1, 2]`} {
+		var r lint.Rule
+		if err := json.Unmarshal([]byte(bad), &r); err == nil {
+			t.Errorf("expected %s to be rejected", bad)
+		}
+	}
+
+	var r lint.Rule
+	if err := json.Unmarshal([]byte(`["warning"]`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Severity != lint.Warning || r.When != lint.Always {
+		t.Errorf("got %+v", r)
+	}
+}
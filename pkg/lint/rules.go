@@ -0,0 +1,363 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// definition is a rule that can be configured
+type definition struct {
+	field string
+	// part returns the text the rule checks. Rules with skipEmpty are not
+	// applied when it is empty
+	part      func(m message) string
+	skipEmpty bool
+	// check reports whether the message passes the rule when it is applied
+	// with "always". It returns an error if the value cannot be used
+	check func(m message, value json.RawMessage) (bool, error)
+	// describe finishes the sentence "<field> must ..."
+	describe func(value json.RawMessage) string
+	// ignoresWhen is set for rules that only make sense one way round, such as
+	// maximum lengths
+	ignoresWhen bool
+}
+
+// bodyStart returns the index of the first line after the blank line that
+// should follow the header
+func bodyStart(m message) int {
+	if len(m.lines) < 2 || strings.TrimSpace(m.lines[1]) == "" {
+		return 2
+	}
+	return 1
+}
+
+var definitions = map[string]definition{
+	"header-max-length":      maxLength("header", func(m message) string { return m.header }),
+	"header-min-length":      minLength("header", func(m message) string { return m.header }),
+	"header-full-stop":       fullStop("header", func(m message) string { return m.header }),
+	"header-trim":            trimmed("header", func(m message) string { return m.header }),
+	"subject-empty":          empty("subject", func(m message) string { return m.subject }),
+	"subject-case":           textCase("subject", func(m message) string { return m.subject }),
+	"subject-full-stop":      fullStop("subject", func(m message) string { return m.subject }),
+	"subject-max-length":     maxLength("subject", func(m message) string { return m.subject }),
+	"subject-min-length":     minLength("subject", func(m message) string { return m.subject }),
+	"subject-imperative":     imperative(),
+	"subject-type-repeat":    typeRepeat(),
+	"type-empty":             empty("type", func(m message) string { return m.typ }),
+	"type-enum":              enum("type", func(m message) string { return m.typ }),
+	"type-case":              textCase("type", func(m message) string { return m.typ }),
+	"type-max-length":        maxLength("type", func(m message) string { return m.typ }),
+	"type-min-length":        minLength("type", func(m message) string { return m.typ }),
+	"scope-empty":            empty("scope", func(m message) string { return m.scope }),
+	"scope-enum":             enum("scope", func(m message) string { return m.scope }),
+	"scope-case":             textCase("scope", func(m message) string { return m.scope }),
+	"scope-max-length":       maxLength("scope", func(m message) string { return m.scope }),
+	"scope-min-length":       minLength("scope", func(m message) string { return m.scope }),
+	"body-empty":             empty("body", func(m message) string { return m.body }),
+	"body-leading-blank":     leadingBlank("body", bodyStart),
+	"body-full-stop":         fullStop("body", func(m message) string { return m.body }),
+	"body-max-length":        maxLength("body", func(m message) string { return m.body }),
+	"body-min-length":        minLength("body", func(m message) string { return m.body }),
+	"body-max-line-length":   maxLineLength("body", func(m message) string { return m.body }),
+	"footer-empty":           empty("footer", func(m message) string { return m.footer }),
+	"footer-leading-blank":   leadingBlank("footer", func(m message) int { return m.footerStart }),
+	"footer-max-length":      maxLength("footer", func(m message) string { return m.footer }),
+	"footer-min-length":      minLength("footer", func(m message) string { return m.footer }),
+	"footer-max-line-length": maxLineLength("footer", func(m message) string { return m.footer }),
+}
+
+func intValue(value json.RawMessage) (int, error) {
+	var n int
+	if err := json.Unmarshal(value, &n); err != nil || n < 0 {
+		return 0, fmt.Errorf("value must be a positive number, got %s", value)
+	}
+	return n, nil
+}
+
+// stringsValue accepts either a single string or a list of strings. An
+// empty value gives the fallback
+func stringsValue(value json.RawMessage, fallback ...string) ([]string, error) {
+	if len(value) == 0 {
+		if len(fallback) == 0 {
+			return nil, fmt.Errorf("value must be a string or a list of strings")
+		}
+		return fallback, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return []string{s}, nil
+	}
+	var list []string
+	if err := json.Unmarshal(value, &list); err != nil {
+		return nil, fmt.Errorf("value must be a string or a list of strings, got %s", value)
+	}
+	return list, nil
+}
+
+func describeList(value json.RawMessage, fallback ...string) string {
+	list, _ := stringsValue(value, fallback...)
+	if len(list) == 1 {
+		return list[0]
+	}
+	return "one of [" + strings.Join(list, ", ") + "]"
+}
+
+func maxLength(field string, part func(message) string) definition {
+	return definition{
+		field: field,
+		part:  part,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			n, err := intValue(value)
+			return utf8.RuneCountInString(part(m)) <= n, err
+		},
+		describe: func(value json.RawMessage) string {
+			return fmt.Sprintf("not be longer than %s characters", value)
+		},
+		ignoresWhen: true,
+	}
+}
+
+func minLength(field string, part func(message) string) definition {
+	return definition{
+		field:     field,
+		part:      part,
+		skipEmpty: true,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			n, err := intValue(value)
+			return utf8.RuneCountInString(part(m)) >= n, err
+		},
+		describe: func(value json.RawMessage) string {
+			return fmt.Sprintf("be at least %s characters", value)
+		},
+		ignoresWhen: true,
+	}
+}
+
+func maxLineLength(field string, part func(message) string) definition {
+	return definition{
+		field: field,
+		part:  part,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			n, err := intValue(value)
+			for _, line := range strings.Split(part(m), "\n") {
+				if utf8.RuneCountInString(line) > n {
+					return false, err
+				}
+			}
+			return true, err
+		},
+		describe: func(value json.RawMessage) string {
+			return fmt.Sprintf("not have lines longer than %s characters", value)
+		},
+		ignoresWhen: true,
+	}
+}
+
+func empty(field string, part func(message) string) definition {
+	return definition{
+		field: field,
+		part:  part,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			return strings.TrimSpace(part(m)) == "", nil
+		},
+		describe: func(value json.RawMessage) string { return "be empty" },
+	}
+}
+
+func fullStop(field string, part func(message) string) definition {
+	return definition{
+		field:     field,
+		part:      part,
+		skipEmpty: true,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			stops, err := stringsValue(value, ".")
+			text := strings.TrimSpace(part(m))
+			for _, stop := range stops {
+				if strings.HasSuffix(text, stop) {
+					return true, err
+				}
+			}
+			return false, err
+		},
+		describe: func(value json.RawMessage) string {
+			return "end with " + describeList(value, ".")
+		},
+	}
+}
+
+func trimmed(field string, part func(message) string) definition {
+	return definition{
+		field: field,
+		part:  part,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			return part(m) == strings.TrimSpace(part(m)), nil
+		},
+		describe: func(value json.RawMessage) string {
+			return "not have leading or trailing whitespace"
+		},
+		ignoresWhen: true,
+	}
+}
+
+func enum(field string, part func(message) string) definition {
+	return definition{
+		field:     field,
+		part:      part,
+		skipEmpty: true,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			allowed, err := stringsValue(value)
+			if err != nil {
+				return true, err
+			}
+			for _, a := range allowed {
+				if part(m) == a {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		describe: func(value json.RawMessage) string {
+			return "be " + describeList(value)
+		},
+	}
+}
+
+func textCase(field string, part func(message) string) definition {
+	return definition{
+		field:     field,
+		part:      part,
+		skipEmpty: true,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			cases, err := stringsValue(value)
+			if err != nil {
+				return true, err
+			}
+			for _, c := range cases {
+				if _, ok := caseCheckers[c]; !ok {
+					return true, fmt.Errorf("unknown case %q", c)
+				}
+			}
+			for _, c := range cases {
+				if caseCheckers[c](part(m)) {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		describe: func(value json.RawMessage) string {
+			return "be " + describeList(value)
+		},
+	}
+}
+
+var (
+	pascalCaseRegex = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	camelCaseRegex  = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	kebabCaseRegex  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	snakeCaseRegex  = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+)
+
+// caseCheckers report whether text is written in each case. Sentence case
+// only needs the first letter to be upper case
+var caseCheckers = map[string]func(text string) bool{
+	"lower-case":    func(text string) bool { return text == strings.ToLower(text) },
+	"upper-case":    func(text string) bool { return text == strings.ToUpper(text) },
+	"sentence-case": func(text string) bool { return startsUpper(text) },
+	"start-case": func(text string) bool {
+		for _, word := range strings.Fields(text) {
+			if !startsUpper(word) {
+				return false
+			}
+		}
+		return true
+	},
+	"pascal-case": pascalCaseRegex.MatchString,
+	"camel-case":  camelCaseRegex.MatchString,
+	"kebab-case":  kebabCaseRegex.MatchString,
+	"snake-case":  snakeCaseRegex.MatchString,
+}
+
+func startsUpper(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsUpper(r)
+}
+
+// leadingBlank checks that the line before the part is blank. start returns
+// the index of the part's first line, or one past the end if it is missing
+func leadingBlank(field string, start func(message) int) definition {
+	return definition{
+		field: field,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			i := start(m)
+			if i < 1 || i >= len(m.lines) {
+				return true, nil
+			}
+			return strings.TrimSpace(m.lines[i-1]) == "", nil
+		},
+		describe: func(value json.RawMessage) string {
+			return "start with a blank line"
+		},
+	}
+}
+
+// nonImperativeVerbs are the verbs whose third person form is flagged. Past
+// tense and -ing forms are flagged for any word
+var nonImperativeVerbs = map[string]bool{
+	"add": true, "allow": true, "bump": true, "change": true, "clean": true,
+	"correct": true, "create": true, "delete": true, "disable": true,
+	"document": true, "drop": true, "enable": true, "fix": true,
+	"handle": true, "implement": true, "improve": true, "introduce": true,
+	"make": true, "merge": true, "move": true, "prevent": true,
+	"refactor": true, "release": true, "remove": true, "rename": true,
+	"replace": true, "revert": true, "set": true, "show": true,
+	"support": true, "update": true, "upgrade": true, "use": true,
+}
+
+// imperative flags subjects starting with "added", "adding" or "adds" rather
+// than "add"
+func imperative() definition {
+	return definition{
+		field:     "subject",
+		part:      func(m message) string { return m.subject },
+		skipEmpty: true,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			words := strings.Fields(strings.ToLower(m.subject))
+			if len(words) == 0 {
+				return true, nil
+			}
+			word := strings.TrimFunc(words[0], func(r rune) bool { return !unicode.IsLetter(r) })
+			switch {
+			case len(word) > 4 && strings.HasSuffix(word, "ed"),
+				len(word) > 5 && strings.HasSuffix(word, "ing"),
+				strings.HasSuffix(word, "es") && nonImperativeVerbs[strings.TrimSuffix(word, "es")],
+				strings.HasSuffix(word, "s") && nonImperativeVerbs[strings.TrimSuffix(word, "s")]:
+				return false, nil
+			}
+			return true, nil
+		},
+		describe: func(value json.RawMessage) string {
+			return "use the imperative mood, such as \"add\" rather than \"added\" or \"adds\""
+		},
+	}
+}
+
+// typeRepeat checks whether the subject starts with its own type, such as
+// "fix: fix the login page"
+func typeRepeat() definition {
+	return definition{
+		field:     "subject",
+		part:      func(m message) string { return m.subject },
+		skipEmpty: true,
+		check: func(m message, value json.RawMessage) (bool, error) {
+			words := strings.Fields(m.subject)
+			return m.typ != "" && len(words) > 0 && strings.EqualFold(words[0], m.typ), nil
+		},
+		describe: func(value json.RawMessage) string {
+			return "repeat the type"
+		},
+	}
+}
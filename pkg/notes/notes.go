@@ -0,0 +1,230 @@
+// Package notes builds release notes from a range of commits, grouped by the
+// ticket each commit belongs to.
+package notes
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Format is the kind of document the notes are rendered as. HTML templates
+// escape their data
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	HTML     Format = "html"
+	Text     Format = "text"
+)
+
+// ParseFormat checks that a format is one of markdown, html or text
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case Markdown, HTML, Text:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected markdown, html or text", s)
+}
+
+// Entry is a commit from the range
+type Entry struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// Change is a single commit in the notes
+type Change struct {
+	Hash string
+	// Subject is the commit's full first line, and Message the part after
+	// the type and scope when it follows a template
+	Subject  string
+	Message  string
+	Type     string
+	Scope    string
+	Breaking bool
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Change) ShortHash() string {
+	return c.Hash[:min(len(c.Hash), 7)]
+}
+
+// Contributor is a commit author or coauthor
+type Contributor struct {
+	Name  string
+	Email string
+}
+
+func (c Contributor) String() string {
+	if c.Email == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s <%s>", c.Name, c.Email)
+}
+
+// Ticket groups the changes made for one ticket
+type Ticket struct {
+	Ticket       string
+	Board        string
+	Types        []string
+	Scopes       []string
+	Changes      []Change
+	Contributors []Contributor
+}
+
+// Notes is the data the templates are executed with
+type Notes struct {
+	Range   string
+	Tickets []Ticket
+	// Other holds the changes without a ticket
+	Other        []Change
+	Contributors []Contributor
+}
+
+// Build groups the entries by ticket, in the order each ticket first appears.
+// ticket returns the ticket number in a commit message, or an empty string
+func Build(c commit.Config, entries []Entry, ticket func(message string) string) Notes {
+	n := Notes{}
+	tickets := map[string]*Ticket{}
+	order := []string{}
+	all := []Contributor{}
+	for _, e := range entries {
+		cm, err := commit.Parse(c, e.Message)
+		subject, _, _ := strings.Cut(strings.TrimLeft(e.Message, "\n"), "\n")
+		change := Change{Hash: e.Hash, Subject: subject, Message: cm.Message}
+		if err == nil {
+			change.Type, change.Scope, change.Breaking = cm.Type, cm.Scope, cm.IsBreakingChange || commit.HasBreakingFooter(e.Message)
+		}
+		people := append([]Contributor{parseContributor(e.Author)}, parseContributors(cm.Coauthors)...)
+		all = addContributors(all, people...)
+
+		number := cm.TicketNumber
+		if number == "" {
+			number = ticket(e.Message)
+		}
+		number = strings.ToUpper(strings.TrimSpace(number))
+		if number == "" {
+			n.Other = append(n.Other, change)
+			continue
+		}
+		t, ok := tickets[number]
+		if !ok {
+			t = &Ticket{Ticket: number, Board: board(number)}
+			tickets[number] = t
+			order = append(order, number)
+		}
+		t.Changes = append(t.Changes, change)
+		t.Types = appendUnique(t.Types, change.Type)
+		t.Scopes = appendUnique(t.Scopes, change.Scope)
+		t.Contributors = addContributors(t.Contributors, people...)
+	}
+	for _, number := range order {
+		n.Tickets = append(n.Tickets, *tickets[number])
+	}
+	n.Contributors = all
+	return n
+}
+
+// board returns the board part of a ticket number such as "ENG-12"
+func board(ticket string) string {
+	if i := strings.LastIndex(ticket, "-"); i > 0 {
+		return ticket[:i]
+	}
+	return ""
+}
+
+func appendUnique(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// addContributors appends the people not already listed, matching them by
+// email or, without one, by name
+func addContributors(list []Contributor, people ...Contributor) []Contributor {
+	for _, p := range people {
+		seen := false
+		for _, c := range list {
+			if (p.Email != "" && strings.EqualFold(c.Email, p.Email)) || (p.Email == "" && c.Name == p.Name) {
+				seen = true
+				break
+			}
+		}
+		if !seen && p.Name != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+func parseContributors(list []string) []Contributor {
+	people := []Contributor{}
+	for _, s := range list {
+		if s != commit.NoCoauthors {
+			people = append(people, parseContributor(s))
+		}
+	}
+	return people
+}
+
+// parseContributor reads "Name <email>"
+func parseContributor(s string) Contributor {
+	name, email, ok := strings.Cut(s, "<")
+	if !ok {
+		return Contributor{Name: strings.TrimSpace(s)}
+	}
+	return Contributor{Name: strings.TrimSpace(name), Email: strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(email), ">"))}
+}
+
+var funcs = map[string]any{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Render executes a template with the notes. An empty path uses the built in
+// template for the format
+func (n Notes) Render(w io.Writer, format Format, path string) error {
+	var text []byte
+	var err error
+	if path == "" {
+		text, err = templates.ReadFile("templates/" + string(format) + ".tmpl")
+	} else {
+		text, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("could not read the template: %w", err)
+	}
+
+	if format == HTML {
+		t, err := htmltemplate.New("notes").Funcs(funcs).Parse(string(text))
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		return t.Execute(w, n)
+	}
+	t, err := template.New("notes").Funcs(funcs).Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return t.Execute(w, n)
+}
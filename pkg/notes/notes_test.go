@@ -0,0 +1,106 @@
+package notes
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+var entries = []Entry{
+	{Hash: "a1b2c3d4e5", Author: "Ann <ann@example.com>", Message: "ENG-7(api): <feat> add exports"},
+	{Hash: "b2c3d4e5f6", Author: "Bob <bob@example.com>", Message: "fix(web): align the buttons\n\nRefs eng-7\n\nCo-authored-by: Cat <cat@example.com>"},
+	{Hash: "c3d4e5f6a7", Author: "Ann <ann@example.com>", Message: "Update README"},
+}
+
+// ticketIn finds ENG tickets in the body, as the board patterns do
+func ticketIn(message string) string {
+	if i := strings.Index(message, "eng-"); i >= 0 {
+		return strings.ToUpper(message[i : i+5])
+	}
+	return ""
+}
+
+func build() Notes {
+	c := commit.Config{MessageTemplate: config.DefaultMessageTemplate, MessageWithTicketTemplate: config.DefaultMessageWithTicketTemplate}
+	return Build(c, entries, ticketIn)
+}
+
+func TestBuild(t *testing.T) {
+	n := build()
+	if len(n.Tickets) != 1 {
+		t.Fatalf("got %d tickets, want 1", len(n.Tickets))
+	}
+	ticket := n.Tickets[0]
+	if ticket.Ticket != "ENG-7" || ticket.Board != "ENG" {
+		t.Errorf("got ticket %q on board %q", ticket.Ticket, ticket.Board)
+	}
+	if !reflect.DeepEqual(ticket.Types, []string{"feat", "fix"}) || !reflect.DeepEqual(ticket.Scopes, []string{"api", "web"}) {
+		t.Errorf("got types %v and scopes %v", ticket.Types, ticket.Scopes)
+	}
+	wantPeople := []Contributor{{"Ann", "ann@example.com"}, {"Bob", "bob@example.com"}, {"Cat", "cat@example.com"}}
+	if !reflect.DeepEqual(ticket.Contributors, wantPeople) {
+		t.Errorf("got ticket contributors %v", ticket.Contributors)
+	}
+	if !reflect.DeepEqual(n.Contributors, wantPeople) {
+		t.Errorf("got contributors %v", n.Contributors)
+	}
+	if len(n.Other) != 1 || n.Other[0].Subject != "Update README" || n.Other[0].ShortHash() != "c3d4e5f" {
+		t.Errorf("got other changes %+v", n.Other)
+	}
+}
+
+func TestRender(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "notes.tmpl")
+	if err := os.WriteFile(custom, []byte(`{{range .Tickets}}{{.Ticket}}: {{len .Changes}} changes by {{len .Contributors}} people{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		path   string
+		want   string
+	}{
+		{"markdown", Markdown, "", "## ENG-7\n\nTypes: feat, fix · Scopes: api, web\n\n- ENG-7(api): <feat> add exports (a1b2c3d)\n"},
+		{"text", Text, "", "ENG-7 (feat, fix; api, web)\n"},
+		{"html escapes", HTML, "", "<li>ENG-7(api): &lt;feat&gt; add exports (<code>a1b2c3d</code>)</li>"},
+		{"custom template", Text, custom, "ENG-7: 2 changes by 3 people"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := build().Render(&b, tt.format, tt.path); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, b.String())
+			}
+		})
+	}
+}
+
+func TestBuildBreakingAndTickets(t *testing.T) {
+	c := commit.Config{MessageTemplate: config.DefaultMessageTemplate, MessageWithTicketTemplate: config.DefaultMessageWithTicketTemplate}
+	n := Build(c, []Entry{
+		{Hash: "a1b2c3d4e5", Author: "Ann <ann@example.com>", Message: "feat(api): drop the v1 routes\n\nRefs eng-9\n\nBREAKING CHANGE: v1 clients must upgrade"},
+		{Hash: "b2c3d4e5f6", Author: "Bob <bob@example.com>", Message: "fix(api): handle empty bodies\n\nRefs ENG-9"},
+	}, func(message string) string {
+		if i := strings.Index(strings.ToLower(message), "eng-"); i >= 0 {
+			return " " + message[i:i+5]
+		}
+		return ""
+	})
+	if len(n.Tickets) != 1 || n.Tickets[0].Ticket != "ENG-9" {
+		t.Fatalf("got tickets %+v, want ENG-9 only", n.Tickets)
+	}
+	changes := n.Tickets[0].Changes
+	if len(changes) != 2 || !changes[0].Breaking || changes[1].Breaking {
+		t.Errorf("got changes %+v, want only the first breaking", changes)
+	}
+}
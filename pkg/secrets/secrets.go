@@ -0,0 +1,178 @@
+// Package secrets scans diffs for credentials and other sensitive content.
+package secrets
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/stefanlogue/meteor/internal/util"
+)
+
+// AllowComment marks a line that should not be scanned
+const AllowComment = "meteor:allow"
+
+// DefaultEntropy is the Shannon entropy, in bits per character, above which
+// a quoted string is reported. Hex strings such as commit hashes top out at 4
+const DefaultEntropy = 4.5
+
+// minEntropyLength is the shortest quoted string checked for entropy
+const minEntropyLength = 20
+
+// Pattern is a named regular expression matching a secret
+type Pattern struct {
+	Name  string
+	Regex *regexp.Regexp
+}
+
+// DefaultPatterns match common credentials
+var DefaultPatterns = []Pattern{
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws.{0,20}(?:secret|key).{0,20}['"][0-9A-Za-z/+]{40}['"]`)},
+	{"private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"JWT", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+}
+
+var quotedRegex = regexp.MustCompile(`["'` + "`" + `]([A-Za-z0-9+/=_\-.]{` + strconv.Itoa(minEntropyLength) + `,})["'` + "`" + `]`)
+
+// Scanner finds secrets in the lines added by a diff
+type Scanner struct {
+	Patterns []Pattern
+	// Allow lists patterns for matches that are not secrets, such as test
+	// fixtures
+	Allow []*regexp.Regexp
+	// AllowPaths lists globs for files that are not scanned
+	AllowPaths []string
+	// Entropy is the threshold for reporting random looking strings, or 0 to
+	// turn the check off
+	Entropy float64
+}
+
+// New returns a scanner using the default patterns
+func New() *Scanner {
+	return &Scanner{
+		Patterns: append([]Pattern{}, DefaultPatterns...),
+		Entropy:  DefaultEntropy,
+	}
+}
+
+// Finding is a possible secret in a file
+type Finding struct {
+	File   string
+	Line   int
+	Kind   string
+	Secret string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Kind, Redact(f.Secret))
+}
+
+// Redact keeps only the start of a secret so that it can be recognised
+// without being shown in full
+func Redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 8)
+}
+
+// Scan checks the lines added in a unified diff without path prefixes, such
+// as the output of "git diff --cached --unified=0 --no-prefix"
+func (s *Scanner) Scan(diff string) []Finding {
+	findings := []Finding{}
+	file := ""
+	line := 0
+	for _, text := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			file = strings.TrimPrefix(text, "+++ ")
+		case strings.HasPrefix(text, "@@"):
+			line = hunkStart(text)
+		case strings.HasPrefix(text, "+"):
+			if !s.allowedPath(file) && !strings.Contains(text, AllowComment) {
+				findings = append(findings, s.scanLine(file, line, text[1:])...)
+			}
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) scanLine(file string, line int, text string) []Finding {
+	findings := []Finding{}
+	add := func(kind string, secret string) {
+		if !s.allowed(secret) {
+			findings = append(findings, Finding{File: file, Line: line, Kind: kind, Secret: secret})
+		}
+	}
+	for _, p := range s.Patterns {
+		for _, match := range p.Regex.FindAllString(text, -1) {
+			add(p.Name, match)
+		}
+	}
+	if s.Entropy > 0 && len(findings) == 0 {
+		for _, match := range quotedRegex.FindAllStringSubmatch(text, -1) {
+			if Entropy(match[1]) >= s.Entropy {
+				add("high entropy string", match[1])
+			}
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) allowed(secret string) bool {
+	for _, re := range s.Allow {
+		if re.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) allowedPath(file string) bool {
+	for _, glob := range s.AllowPaths {
+		if util.MatchGlob(glob, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// hunkStart returns the first new line number from a hunk header such as
+// "@@ -1,2 +3,4 @@"
+func hunkStart(header string) int {
+	fields := strings.Fields(header)
+	for _, f := range fields {
+		if strings.HasPrefix(f, "+") {
+			start, _, _ := strings.Cut(f[1:], ",")
+			n, _ := strconv.Atoi(start)
+			return n
+		}
+	}
+	return 0
+}
+
+// Entropy returns the Shannon entropy of s in bits per character
+func Entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	total := float64(len([]rune(s)))
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / total
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,205 @@
+package spell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// affix is a single prefix or suffix rule from an .aff file
+type affix struct {
+	prefix    bool
+	cross     bool
+	strip     string
+	add       string
+	condition *regexp.Regexp
+}
+
+// apply returns the word with the affix applied, or false if the word does
+// not meet the rule's condition
+func (a affix) apply(word string) (string, bool) {
+	if !a.condition.MatchString(word) {
+		return "", false
+	}
+	if a.prefix {
+		if !strings.HasPrefix(word, a.strip) {
+			return "", false
+		}
+		return a.add + word[len(a.strip):], true
+	}
+	if !strings.HasSuffix(word, a.strip) {
+		return "", false
+	}
+	return word[:len(word)-len(a.strip)] + a.add, true
+}
+
+// affixFile holds the parts of an .aff file needed to expand a word list
+type affixFile struct {
+	flagType string
+	affixes  map[string][]affix
+}
+
+// parseAffixes reads the PFX, SFX and FLAG entries of an .aff file. Every
+// other option is ignored
+func parseAffixes(r io.Reader) (*affixFile, error) {
+	af := &affixFile{affixes: map[string][]affix{}}
+	scanner := bufio.NewScanner(r)
+	// cross records whether each rule allows cross products, and that its
+	// header has been read
+	cross := map[string]bool{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "FLAG":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: FLAG needs a type", line)
+			}
+			af.flagType = fields[1]
+		case "PFX", "SFX":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("line %d: %s needs at least four fields", line, fields[0])
+			}
+			key := fields[0] + " " + fields[1]
+			// the first line of each rule is a header: flag, cross product, count
+			allowed, ok := cross[key]
+			if !ok {
+				cross[key] = fields[2] == "Y"
+				continue
+			}
+			a, err := parseAffix(fields)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			a.cross = allowed
+			af.affixes[fields[1]] = append(af.affixes[fields[1]], a)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return af, nil
+}
+
+func parseAffix(fields []string) (affix, error) {
+	a := affix{prefix: fields[0] == "PFX", strip: fields[2], add: fields[3]}
+	if a.strip == "0" {
+		a.strip = ""
+	}
+	if a.add == "0" {
+		a.add = ""
+	}
+	// continuation classes on the affix ("s/XY") are not supported
+	a.add, _, _ = strings.Cut(a.add, "/")
+
+	condition := "."
+	if len(fields) > 4 {
+		condition = fields[4]
+	}
+	pattern := conditionPattern(condition)
+	if a.prefix {
+		pattern = "^" + pattern
+	} else {
+		pattern += "$"
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return a, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+	a.condition = re
+	return a, nil
+}
+
+// conditionPattern converts an affix condition, which only supports ".",
+// and character classes, into a regular expression
+func conditionPattern(condition string) string {
+	var b strings.Builder
+	inClass := false
+	for _, r := range condition {
+		switch {
+		case r == '[':
+			inClass = true
+			b.WriteRune(r)
+		case r == ']':
+			inClass = false
+			b.WriteRune(r)
+		case r == '^' && inClass, r == '.' && !inClass:
+			b.WriteRune(r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// splitFlags splits the flags of a dictionary word according to the FLAG
+// option of the .aff file
+func (af *affixFile) splitFlags(flags string) []string {
+	switch af.flagType {
+	case "long":
+		split := []string{}
+		for i := 0; i+1 < len(flags); i += 2 {
+			split = append(split, flags[i:i+2])
+		}
+		return split
+	case "num":
+		split := []string{}
+		for _, f := range strings.Split(flags, ",") {
+			if _, err := strconv.Atoi(f); err == nil {
+				split = append(split, f)
+			}
+		}
+		return split
+	default:
+		split := make([]string, 0, utf8.RuneCountInString(flags))
+		for _, r := range flags {
+			split = append(split, string(r))
+		}
+		return split
+	}
+}
+
+// expand returns the word with every combination of its affixes applied.
+// Prefixes and suffixes are only combined when both allow cross products
+func (af *affixFile) expand(word string, flags []string) []string {
+	forms := []string{word}
+	var prefixes, suffixes []affix
+	for _, flag := range flags {
+		for _, a := range af.affixes[flag] {
+			if a.prefix {
+				prefixes = append(prefixes, a)
+			} else {
+				suffixes = append(suffixes, a)
+			}
+		}
+	}
+	for _, s := range suffixes {
+		withSuffix, ok := s.apply(word)
+		if !ok {
+			continue
+		}
+		forms = append(forms, withSuffix)
+		for _, p := range prefixes {
+			if !p.cross || !s.cross {
+				continue
+			}
+			if both, ok := p.apply(withSuffix); ok {
+				forms = append(forms, both)
+			}
+		}
+	}
+	for _, p := range prefixes {
+		if withPrefix, ok := p.apply(word); ok {
+			forms = append(forms, withPrefix)
+		}
+	}
+	return forms
+}
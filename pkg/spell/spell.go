@@ -0,0 +1,201 @@
+// Package spell is a small offline spell checker for commit messages that
+// reads Hunspell dictionaries.
+package spell
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed dictionaries
+var dictionaries embed.FS
+
+// Dictionary is a set of known words
+type Dictionary struct {
+	words map[string]struct{}
+}
+
+// New returns an empty dictionary
+func New() *Dictionary {
+	return &Dictionary{words: map[string]struct{}{}}
+}
+
+// English returns a dictionary loaded with the bundled English word list
+func English() *Dictionary {
+	d := New()
+	aff, _ := dictionaries.Open("dictionaries/en_US.aff")
+	defer aff.Close()
+	dic, _ := dictionaries.Open("dictionaries/en_US.dic")
+	defer dic.Close()
+	if err := d.Load(aff, dic); err != nil {
+		panic(fmt.Sprintf("bundled dictionary is invalid: %v", err))
+	}
+	return d
+}
+
+// Load adds the words from a Hunspell dictionary, given its .aff and .dic
+// files, with their affixes applied
+func (d *Dictionary) Load(aff io.Reader, dic io.Reader) error {
+	af, err := parseAffixes(aff)
+	if err != nil {
+		return fmt.Errorf("error reading affixes: %w", err)
+	}
+
+	scanner := bufio.NewScanner(dic)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// the first line is the approximate word count
+		if first {
+			first = false
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// morphological fields follow the word after whitespace
+		line = strings.Fields(line)[0]
+		word, flags, _ := strings.Cut(line, "/")
+		d.Add(af.expand(word, af.splitFlags(flags))...)
+	}
+	return scanner.Err()
+}
+
+// LoadFiles adds the Hunspell dictionary at path, given without the .aff and
+// .dic extensions
+func (d *Dictionary) LoadFiles(path string) error {
+	aff, err := os.Open(path + ".aff")
+	if err != nil {
+		return err
+	}
+	defer aff.Close()
+	dic, err := os.Open(path + ".dic")
+	if err != nil {
+		return err
+	}
+	defer dic.Close()
+	if err := d.Load(aff, dic); err != nil {
+		return fmt.Errorf("error loading %s: %w", path, err)
+	}
+	return nil
+}
+
+// Add adds words to the dictionary as they are written
+func (d *Dictionary) Add(words ...string) {
+	for _, w := range words {
+		if w != "" {
+			d.words[w] = struct{}{}
+		}
+	}
+}
+
+// Contains reports whether the word is known. Capitalised and upper case
+// forms of lower case words are accepted
+func (d *Dictionary) Contains(word string) bool {
+	if _, ok := d.words[word]; ok {
+		return true
+	}
+	lower := strings.ToLower(word)
+	if _, ok := d.words[lower]; ok {
+		return word == strings.ToUpper(word) || word == capitalise(lower)
+	}
+	return false
+}
+
+func capitalise(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
+
+// Misspelling is an unknown word and its byte offset in the checked text
+type Misspelling struct {
+	Word   string
+	Offset int
+}
+
+var (
+	codeSpanRegex = regexp.MustCompile("`[^`]*`")
+	tokenRegex    = regexp.MustCompile(`[^\s]+`)
+	wordRegex     = regexp.MustCompile(`[\p{L}']+`)
+)
+
+// Check returns the words in text that are not in the dictionary. Text in
+// backticks and anything that looks like code, a path, a URL or a ticket
+// number is skipped
+func (d *Dictionary) Check(text string) []Misspelling {
+	// blank out code spans so that offsets still line up
+	text = codeSpanRegex.ReplaceAllStringFunc(text, func(s string) string {
+		return strings.Repeat(" ", len(s))
+	})
+
+	misspellings := []Misspelling{}
+	for _, loc := range tokenRegex.FindAllStringIndex(text, -1) {
+		token := text[loc[0]:loc[1]]
+		if !isProse(token) {
+			continue
+		}
+		for _, wl := range wordRegex.FindAllStringIndex(token, -1) {
+			word := strings.Trim(token[wl[0]:wl[1]], "'")
+			if word == "" || isAcronym(word) || hasInnerCapital(word) {
+				continue
+			}
+			if d.Contains(word) || d.Contains(strings.TrimSuffix(word, "'s")) {
+				continue
+			}
+			offset := loc[0] + wl[0] + strings.Index(token[wl[0]:wl[1]], word)
+			misspellings = append(misspellings, Misspelling{Word: word, Offset: offset})
+		}
+	}
+	return misspellings
+}
+
+// isProse reports whether the token is ordinary text rather than an
+// identifier, path, URL, email address or number
+func isProse(token string) bool {
+	trimmed := strings.TrimFunc(token, func(r rune) bool {
+		return unicode.IsPunct(r) && r != '_'
+	})
+	if trimmed == "" {
+		return false
+	}
+	return !strings.ContainsFunc(trimmed, func(r rune) bool {
+		return unicode.IsDigit(r) || strings.ContainsRune("_/\\.@:=#<>(){}[]$%&*+|~^", r)
+	})
+}
+
+func isAcronym(word string) bool {
+	return utf8.RuneCountInString(word) > 1 && word == strings.ToUpper(word)
+}
+
+// hasInnerCapital reports whether the word looks like camelCase or PascalCase
+func hasInnerCapital(word string) bool {
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+var identifierRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]{2,}`)
+
+// Identifiers returns the distinct identifiers in source code, such as a
+// diff of the staged changes, to add to the dictionary as project words
+func Identifiers(source string) []string {
+	seen := map[string]bool{}
+	identifiers := []string{}
+	for _, id := range identifierRegex.FindAllString(source, -1) {
+		if !seen[id] {
+			seen[id] = true
+			identifiers = append(identifiers, id)
+		}
+	}
+	return identifiers
+}
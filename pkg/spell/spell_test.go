@@ -0,0 +1,99 @@
+package spell
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testAffixes = `
+SFX S Y 2
+SFX S y ies [^aeiou]y
+SFX S 0 s [^y]
+
+SFX D Y 2
+SFX D 0 d e
+SFX D 0 ed [^e]
+
+PFX U Y 1
+PFX U 0 un .
+`
+
+func TestLoad(t *testing.T) {
+	d := New()
+	err := d.Load(strings.NewReader(testAffixes), strings.NewReader("3\nquery/S\nsave/DU\nlock/DSU\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range []string{"query", "queries", "save", "saved", "unsave", "unsaved", "lock", "locks", "locked", "unlocked", "unlocks"} {
+		if !d.Contains(word) {
+			t.Errorf("expected %q to be known", word)
+		}
+	}
+	for _, word := range []string{"querys", "saveed", "saves", "unquery"} {
+		if d.Contains(word) {
+			t.Errorf("did not expect %q to be known", word)
+		}
+	}
+}
+
+func TestLongFlags(t *testing.T) {
+	d := New()
+	err := d.Load(strings.NewReader("FLAG long\nSFX Aa Y 1\nSFX Aa 0 s .\n"), strings.NewReader("1\nword/Aa\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Contains("words") {
+		t.Error("expected long flags to be applied")
+	}
+}
+
+func TestContains(t *testing.T) {
+	d := New()
+	d.Add("commit", "GitHub")
+	tests := map[string]bool{
+		"commit": true,
+		"Commit": true,
+		"COMMIT": true,
+		"cOmmit": false,
+		"GitHub": true,
+		"github": false,
+	}
+	for word, want := range tests {
+		if got := d.Contains(word); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	d := English()
+	d.Add("meteor")
+
+	tests := []struct {
+		text string
+		want []Misspelling
+	}{
+		{"fix: add teh missing check", []Misspelling{{"teh", 9}}},
+		{"Fixed the user's login page.", []Misspelling{}},
+		{"skip `someFunc` and parseLog, ABC-123, ./path/to/file and https://example.com", []Misspelling{}},
+		{"feat(api): suport meteor colours", []Misspelling{{"suport", 11}}},
+		{"don't break the build", []Misspelling{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := d.Check(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	got := Identifiers("+func parseLog(out string) {\n+\treturn parseLog(chdir)\n")
+	want := []string{"func", "parseLog", "out", "string", "return", "chdir"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is how a report is written out
+type Format string
+
+const (
+	Table Format = "table"
+	CSV   Format = "csv"
+	JSON  Format = "json"
+)
+
+// allPeriods names the totals across every period in CSV output
+const allPeriods = "all"
+
+// tablePeriods is how many of the latest periods get a column in the
+// breakdowns of the table output
+const tablePeriods = 6
+
+// Write writes the report in the given format
+func (r Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case Table, "":
+		return r.WriteTable(w)
+	case CSV:
+		return r.WriteCSV(w)
+	case JSON:
+		return r.WriteJSON(w)
+	}
+	return fmt.Errorf("unknown format %q, expected table, csv or json", format)
+}
+
+// WriteJSON writes the report as indented JSON
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one row per metric, name and period, with the totals
+// across every period under "all"
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"metric", "name", "period", "value"})
+	summary := func(period string, s Summary) {
+		_ = cw.Write([]string{"commits", "", period, strconv.Itoa(s.Commits)})
+		_ = cw.Write([]string{"conventional", "", period, strconv.Itoa(s.Conventional)})
+		_ = cw.Write([]string{"breaking", "", period, strconv.Itoa(s.Breaking)})
+		_ = cw.Write([]string{"average_subject_length", "", period, strconv.FormatFloat(s.AverageSubjectLength, 'f', 1, 64)})
+	}
+	summary(allPeriods, r.Summary)
+	for _, p := range r.Periods {
+		summary(p, r.ByPeriod[p])
+	}
+	for _, group := range r.groups() {
+		for _, c := range group.counts {
+			_ = cw.Write([]string{group.metric, c.Name, allPeriods, strconv.Itoa(c.Commits)})
+			for _, p := range r.Periods {
+				if n := c.ByPeriod[p]; n > 0 {
+					_ = cw.Write([]string{group.metric, c.Name, p, strconv.Itoa(n)})
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTable writes the report as aligned tables for the terminal
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Commits\t%d\n", r.Commits)
+	fmt.Fprintf(tw, "Conventional\t%d (%s)\n", r.Conventional, percent(r.ConventionalRatio()))
+	fmt.Fprintf(tw, "Breaking changes\t%d (%s)\n", r.Breaking, percent(r.BreakingRatio()))
+	fmt.Fprintf(tw, "Average subject length\t%.1f\n", r.AverageSubjectLength)
+
+	fmt.Fprintf(tw, "\n%s\tCOMMITS\tCONVENTIONAL\tBREAKING\tAVG SUBJECT\n", strings.ToUpper(string(r.Period)))
+	for _, p := range r.Periods {
+		s := r.ByPeriod[p]
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%.1f\n", p, s.Commits, percent(s.ConventionalRatio()), s.Breaking, s.AverageSubjectLength)
+	}
+
+	recent := r.Periods[max(0, len(r.Periods)-tablePeriods):]
+	for _, group := range r.groups() {
+		if len(group.counts) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "\n%s\tCOMMITS", strings.ToUpper(group.metric))
+		for _, p := range recent {
+			fmt.Fprintf(tw, "\t%s", p)
+		}
+		fmt.Fprintln(tw)
+		for _, c := range group.counts {
+			fmt.Fprintf(tw, "%s\t%d", c.Name, c.Commits)
+			for _, p := range recent {
+				fmt.Fprintf(tw, "\t%d", c.ByPeriod[p])
+			}
+			fmt.Fprintln(tw)
+		}
+	}
+	return tw.Flush()
+}
+
+type group struct {
+	metric string
+	counts []Count
+}
+
+func (r Report) groups() []group {
+	return []group{
+		{"type", r.Types},
+		{"scope", r.Scopes},
+		{"board", r.Boards},
+		{"author", r.Authors},
+	}
+}
+
+func percent(f float64) string {
+	return fmt.Sprintf("%.1f%%", f*100)
+}
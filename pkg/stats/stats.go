@@ -0,0 +1,177 @@
+// Package stats summarises a repository's commit history against its commit
+// message templates.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+)
+
+// Period is the length of time commits are grouped by
+type Period string
+
+const (
+	Week  Period = "week"
+	Month Period = "month"
+	Year  Period = "year"
+)
+
+// ParsePeriod checks that a period is one of week, month or year
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(s); p {
+	case Week, Month, Year:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown period %q, expected week, month or year", s)
+}
+
+// Key returns the name of the period a time falls in, such as "2024-W07",
+// "2024-02" or "2024"
+func (p Period) Key(t time.Time) string {
+	switch p {
+	case Week:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case Year:
+		return t.Format("2006")
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+// Entry is a commit from the history
+type Entry struct {
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// Summary holds the totals for a set of commits
+type Summary struct {
+	Commits int `json:"commits"`
+	// Conventional counts the commits whose subject matches a message
+	// template
+	Conventional         int     `json:"conventional"`
+	Breaking             int     `json:"breaking"`
+	AverageSubjectLength float64 `json:"averageSubjectLength"`
+	subjectLength        int
+}
+
+// ConventionalRatio returns the share of commits that match a template
+func (s Summary) ConventionalRatio() float64 {
+	return ratio(s.Conventional, s.Commits)
+}
+
+// BreakingRatio returns the share of commits that are breaking changes
+func (s Summary) BreakingRatio() float64 {
+	return ratio(s.Breaking, s.Commits)
+}
+
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func (s *Summary) add(subject string, conventional, breaking bool) {
+	s.Commits++
+	if conventional {
+		s.Conventional++
+	}
+	if breaking {
+		s.Breaking++
+	}
+	s.subjectLength += utf8.RuneCountInString(subject)
+	s.AverageSubjectLength = float64(s.subjectLength) / float64(s.Commits)
+}
+
+// Count is the number of commits with a type, scope, board or author
+type Count struct {
+	Name     string         `json:"name"`
+	Commits  int            `json:"commits"`
+	ByPeriod map[string]int `json:"byPeriod"`
+}
+
+// Report summarises a history, overall and for each period
+type Report struct {
+	Summary
+	Period Period `json:"period"`
+	// Periods lists the periods with commits, oldest first
+	Periods  []string           `json:"periods"`
+	ByPeriod map[string]Summary `json:"byPeriod"`
+	// Types, Scopes and Boards only count conventional commits
+	Types   []Count `json:"types"`
+	Scopes  []Count `json:"scopes"`
+	Boards  []Count `json:"boards"`
+	Authors []Count `json:"authors"`
+}
+
+// Analyse parses every entry with the message templates and groups the
+// results by period
+func Analyse(c commit.Config, entries []Entry, period Period) Report {
+	r := Report{Period: period, ByPeriod: map[string]Summary{}}
+	types, scopes, boards, authors := counter{}, counter{}, counter{}, counter{}
+	for _, e := range entries {
+		key := period.Key(e.Date)
+		cm, err := commit.Parse(c, e.Message)
+		conventional := err == nil
+		breaking := conventional && (cm.IsBreakingChange || commit.HasBreakingFooter(e.Message))
+		subject, _, _ := strings.Cut(strings.TrimLeft(e.Message, "\n"), "\n")
+
+		r.Summary.add(subject, conventional, breaking)
+		s := r.ByPeriod[key]
+		s.add(subject, conventional, breaking)
+		r.ByPeriod[key] = s
+
+		if conventional {
+			types.add(cm.Type, key)
+			for _, scope := range commit.SplitScopes(c, cm.Scope) {
+				scopes.add(scope, key)
+			}
+			boards.add(cm.Board, key)
+		}
+		authors.add(e.Author, key)
+	}
+	for key := range r.ByPeriod {
+		r.Periods = append(r.Periods, key)
+	}
+	sort.Strings(r.Periods)
+	r.Types, r.Scopes, r.Boards, r.Authors = types.sorted(), scopes.sorted(), boards.sorted(), authors.sorted()
+	return r
+}
+
+type counter map[string]*Count
+
+func (c counter) add(name, period string) {
+	if name == "" {
+		return
+	}
+	count, ok := c[name]
+	if !ok {
+		count = &Count{Name: name, ByPeriod: map[string]int{}}
+		c[name] = count
+	}
+	count.Commits++
+	count.ByPeriod[period]++
+}
+
+// sorted returns the counts with the most commits first
+func (c counter) sorted() []Count {
+	counts := make([]Count, 0, len(c))
+	for _, count := range c {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Commits != counts[j].Commits {
+			return counts[i].Commits > counts[j].Commits
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stefanlogue/meteor/pkg/commit"
+	"github.com/stefanlogue/meteor/pkg/config"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+var entries = []Entry{
+	{Author: "Ann", Date: date("2024-01-10"), Message: "feat(api)!: drop the v1 endpoints"},
+	{Author: "Ann", Date: date("2024-01-20"), Message: "ENG-12(web): <fix> align the buttons"},
+	{Author: "Bob", Date: date("2024-02-02"), Message: "Update README"},
+	{Author: "Bob", Date: date("2024-02-03"), Message: "feat: add exports\n\nBREAKING CHANGE: the CSV columns changed"},
+}
+
+func TestAnalyse(t *testing.T) {
+	c := commit.Config{MessageTemplate: config.DefaultMessageTemplate, MessageWithTicketTemplate: config.DefaultMessageWithTicketTemplate}
+	r := Analyse(c, entries, Month)
+
+	if r.Commits != 4 || r.Conventional != 3 || r.Breaking != 2 {
+		t.Errorf("got %d commits, %d conventional and %d breaking", r.Commits, r.Conventional, r.Breaking)
+	}
+	if got := r.ConventionalRatio(); got != 0.75 {
+		t.Errorf("got conventional ratio %v, want 0.75", got)
+	}
+	if !reflect.DeepEqual(r.Periods, []string{"2024-01", "2024-02"}) {
+		t.Errorf("got periods %v", r.Periods)
+	}
+	if got := r.ByPeriod["2024-02"]; got.Commits != 2 || got.Conventional != 1 || got.AverageSubjectLength != 15 {
+		t.Errorf("got February summary %+v", got)
+	}
+
+	tests := []struct {
+		name   string
+		counts []Count
+		want   []Count
+	}{
+		{"types", r.Types, []Count{
+			{Name: "feat", Commits: 2, ByPeriod: map[string]int{"2024-01": 1, "2024-02": 1}},
+			{Name: "fix", Commits: 1, ByPeriod: map[string]int{"2024-01": 1}},
+		}},
+		{"scopes", r.Scopes, []Count{
+			{Name: "api", Commits: 1, ByPeriod: map[string]int{"2024-01": 1}},
+			{Name: "web", Commits: 1, ByPeriod: map[string]int{"2024-01": 1}},
+		}},
+		{"boards", r.Boards, []Count{
+			{Name: "ENG", Commits: 1, ByPeriod: map[string]int{"2024-01": 1}},
+		}},
+		{"authors", r.Authors, []Count{
+			{Name: "Ann", Commits: 2, ByPeriod: map[string]int{"2024-01": 2}},
+			{Name: "Bob", Commits: 2, ByPeriod: map[string]int{"2024-02": 2}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.counts, tt.want) {
+				t.Errorf("got %+v, want %+v", tt.counts, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodKey(t *testing.T) {
+	d := date("2024-02-14")
+	tests := []struct {
+		period Period
+		want   string
+	}{
+		{Week, "2024-W07"},
+		{Month, "2024-02"},
+		{Year, "2024"},
+	}
+	for _, tt := range tests {
+		if got := tt.period.Key(d); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.period, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	c := commit.Config{MessageTemplate: config.DefaultMessageTemplate}
+	r := Analyse(c, entries[:1], Month)
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{Table, []string{"Conventional            1 (100.0%)", "TYPE  COMMITS  2024-01\nfeat  1        1"}},
+		{CSV, []string{"metric,name,period,value\ncommits,,all,1\n", "type,feat,2024-01,1\n"}},
+		{JSON, []string{`"conventional": 1,`, `"name": "api",`}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b bytes.Buffer
+			if err := r.Write(&b, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("output does not contain %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+
+	if err := r.Write(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestAnalyseSplitsScopes(t *testing.T) {
+	c := commit.Config{MessageTemplate: config.DefaultMessageTemplate, ScopeDelimiter: "+", MaxScopes: 2}
+	r := Analyse(c, []Entry{
+		{Author: "Ann", Date: date("2024-01-10"), Message: "feat(api+web): share the export button"},
+		{Author: "Ann", Date: date("2024-01-11"), Message: "fix(api): handle empty exports"},
+	}, Month)
+	want := []Count{
+		{Name: "api", Commits: 2, ByPeriod: map[string]int{"2024-01": 2}},
+		{Name: "web", Commits: 1, ByPeriod: map[string]int{"2024-01": 1}},
+	}
+	if !reflect.DeepEqual(r.Scopes, want) {
+		t.Errorf("got %+v, want %+v", r.Scopes, want)
+	}
+}
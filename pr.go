@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+// prTemplateMarker is replaced with the list of changes in a pull request
+// template. Without it the changes go above the template
+const prTemplateMarker = "<!-- meteor:changes -->"
+
+// prTemplatePaths are where hosting services look for a pull request template
+var prTemplatePaths = []string{
+	".github/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+	".gitlab/merge_request_templates/Default.md",
+}
+
+// runPRText writes a pull request title and description for the commits on
+// the current branch
+func runPRText(config cfg.Settings, args []string) {
+	entries, _ := branchCommits("pr-text", args)
+
+	squashed := proposeSquash(config.Commit(), config.Prefixes, entries, getBranchTicket(boardNames(config)), "")
+	title, err := cmt.Subject(config.Commit(), squashed)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+
+	prTemplate := ""
+	path := templateFlag
+	if path == "" {
+		path = findPRTemplate()
+	}
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			fail(ErrorString, err)
+		}
+		prTemplate = string(b)
+	}
+	description := prDescription(config, entries, squashed.TicketNumber, prTemplate)
+	text := title + "\n\n" + description
+
+	if prOutput == "" || prOutput == "-" {
+		fmt.Print(text)
+		return
+	}
+	if err := os.WriteFile(prOutput, []byte(text), 0o644); err != nil {
+		fail(ErrorString, err)
+	}
+}
+
+// findPRTemplate returns the repository's pull request template, or an empty
+// string if it has none
+func findPRTemplate() string {
+	for _, path := range prTemplatePaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// prDescription lists the changes grouped by type, with breaking changes
+// first and links to the tickets, inside the pull request template if there
+// is one
+func prDescription(config cfg.Settings, entries []logEntry, branchTicket string, prTemplate string) string {
+	c := config.Commit()
+	groups := map[string][]string{}
+	breaking := []string{}
+	tickets := appendNew(nil, branchTicket)
+	for _, e := range entries {
+		if slices.ContainsFunc(autosquashPrefixes, func(p string) bool { return strings.HasPrefix(e.Subject(), p) }) {
+			continue
+		}
+		cm, err := cmt.Parse(c, e.Message)
+		if err != nil {
+			groups[""] = append(groups[""], e.Subject())
+			continue
+		}
+		line := cm.Message
+		if cm.Scope != "" {
+			line = fmt.Sprintf("**%s:** %s", cm.Scope, cm.Message)
+		}
+		groups[cm.Type] = append(groups[cm.Type], line)
+		if cm.IsBreakingChange || cmt.HasBreakingFooter(e.Message) {
+			breaking = append(breaking, line)
+		}
+		tickets = appendNew(tickets, cm.TicketNumber)
+	}
+
+	var b strings.Builder
+	if len(tickets) > 0 {
+		links := make([]string, len(tickets))
+		for i, t := range tickets {
+			links[i] = ticketLink(config.TicketURL, t)
+		}
+		fmt.Fprintf(&b, "%s\n\n", catalogue.T("pr.tickets", strings.Join(links, ", ")))
+	}
+	writeSection(&b, "⚠️ "+catalogue.T("pr.breaking"), breaking)
+	types := append([]string{}, config.Prefixes...)
+	for t := range groups {
+		if t != "" && !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	slices.SortStableFunc(types[len(config.Prefixes):], strings.Compare)
+	for _, t := range append(types, "") {
+		title := t
+		if t == "" {
+			title = catalogue.T("pr.other")
+		}
+		writeSection(&b, title, groups[t])
+	}
+
+	changes := strings.TrimRight(b.String(), "\n") + "\n"
+	if prTemplate == "" {
+		return changes
+	}
+	if strings.Contains(prTemplate, prTemplateMarker) {
+		return strings.Replace(prTemplate, prTemplateMarker, strings.TrimRight(changes, "\n"), 1)
+	}
+	return changes + "\n" + prTemplate
+}
+
+func writeSection(b *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "### %s\n\n", title)
+	for _, line := range lines {
+		fmt.Fprintf(b, "- %s\n", line)
+	}
+	b.WriteString("\n")
+}
+
+// ticketLink returns a Markdown link to the ticket, or just the ticket when
+// there is no URL template
+func ticketLink(urlTemplate string, ticket string) string {
+	if urlTemplate == "" {
+		return ticket
+	}
+	t, err := template.New("ticketUrl").Parse(urlTemplate)
+	if err != nil {
+		return ticket
+	}
+	var url bytes.Buffer
+	c := cmt.Commit{TicketNumber: ticket}
+	if i := strings.LastIndex(ticket, "-"); i > 0 {
+		c.Board = ticket[:i]
+	}
+	if err := t.Execute(&url, c); err != nil {
+		return ticket
+	}
+	return fmt.Sprintf("[%s](%s)", ticket, url.String())
+}
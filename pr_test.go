@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestPRDescription(t *testing.T) {
+	config := cfg.Default()
+	config.TicketURL = "https://jira.example.com/browse/{{.TicketNumber}}"
+	entries := []logEntry{
+		{Message: "feat(api): add the export endpoint"},
+		{Message: "fixup! feat(api): add the export endpoint"},
+		{Message: "fix: stream large exports\n\nBREAKING CHANGE: the endpoint now streams"},
+		{Message: "Tidy up"},
+	}
+	changes := "Tickets: [ENG-12](https://jira.example.com/browse/ENG-12)\n\n" +
+		"### ⚠️ Breaking changes\n\n- stream large exports\n\n" +
+		"### feat\n\n- **api:** add the export endpoint\n\n" +
+		"### fix\n\n- stream large exports\n\n" +
+		"### Other changes\n\n- Tidy up\n"
+
+	cases := []struct {
+		Desc       string
+		prTemplate string
+		want       string
+	}{
+		{"it should list the changes", "", changes},
+		{"it should fill in the template marker", "## Changes\n\n<!-- meteor:changes -->\n\n## Testing\n", "## Changes\n\n" + changes + "\n## Testing\n"},
+		{"it should put the changes above a template without the marker", "## Testing\n", changes + "\n## Testing\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, prDescription(config, entries, "ENG-12", tc.prTemplate))
+		})
+	}
+}
+
+func TestTicketLink(t *testing.T) {
+	cases := []struct {
+		Desc        string
+		urlTemplate string
+		want        string
+	}{
+		{"it should link the ticket", "https://jira.example.com/browse/{{.TicketNumber}}", "[ENG-12](https://jira.example.com/browse/ENG-12)"},
+		{"it should give the board", "https://example.com/{{.Board}}/issues/{{.TicketNumber}}", "[ENG-12](https://example.com/ENG/issues/ENG-12)"},
+		{"it should not link without a URL", "", "ENG-12"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, ticketLink(tc.urlTemplate, "ENG-12"))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/internal/wizard"
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+const (
+	revertPrefix  = "revert"
+	revertTrailer = "Reverts"
+)
+
+// runRevert reverts a commit and runs the wizard with a message prefilled
+// from the reverted commit
+func runRevert(config cfg.Settings, theme *huh.Theme, args []string) {
+	if len(args) < 1 {
+		fail("Usage: meteor revert <rev>")
+	}
+	sha, err := resolveCommit(args[0])
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	message, err := getCommitMessage(sha)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	if err := revertWithoutCommit(sha); err != nil {
+		fail(ErrorString, err)
+	}
+
+	if !slices.Contains(config.Prefixes, revertPrefix) {
+		config.Prefixes = append(config.Prefixes, revertPrefix)
+		config.SelectablePrefixes = append(config.SelectablePrefixes, cfg.NewOption(catalogue.T("revert.option"), revertPrefix))
+	}
+
+	state, err := newWizard(config, revertPrefill(config.Commit(), sha, message)).Run(wizard.Interactive{Theme: theme})
+	if err != nil {
+		fail(ErrorString, err)
+	}
+
+	rawCommitCommand, printableCommitCommand := buildCommitCommand(state.Subject, state.Body, args[1:])
+	if !state.Confirmed {
+		commitAborted(printableCommitCommand)
+		fmt.Printf("%s\n\n", messages.warnf("%s", catalogue.T("revert.staged")))
+		return
+	}
+	if err := commit(rawCommitCommand); err != nil {
+		commitFailed(printableCommitCommand, err)
+	}
+}
+
+// revertPrefill returns the commit to start a revert from, keeping the board,
+// ticket, scope and message of the reverted commit
+func revertPrefill(c cmt.Config, sha string, message string) cmt.Commit {
+	original, err := cmt.Parse(c, message)
+	if err != nil && !errors.Is(err, cmt.ErrNoMatch) {
+		original = cmt.Commit{}
+	}
+
+	subject, _, _ := strings.Cut(message, "\n")
+	return cmt.Commit{
+		Board:        original.Board,
+		TicketNumber: original.TicketNumber,
+		Type:         revertPrefix,
+		Scope:        original.Scope,
+		Message:      original.Message,
+		Body:         fmt.Sprintf("This reverts %q.", subject),
+		Trailers:     []cmt.Trailer{{Key: revertTrailer, Value: sha}},
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestRevertPrefill(t *testing.T) {
+	cases := []struct {
+		Desc    string
+		message string
+		want    cmt.Commit
+	}{
+		{
+			"it should keep the scope and message",
+			"feat(api)!: add pagination\n\nbody\n\nCo-authored-by: Jane <jane@example.com>",
+			cmt.Commit{
+				Type:     "revert",
+				Scope:    "api",
+				Message:  "add pagination",
+				Body:     `This reverts "feat(api)!: add pagination".`,
+				Trailers: []cmt.Trailer{{Key: "Reverts", Value: "abc123"}},
+			},
+		},
+		{
+			"it should keep the ticket",
+			"COMP-12: <fix> handle nil",
+			cmt.Commit{
+				Board:        "COMP",
+				TicketNumber: "COMP-12",
+				Type:         "revert",
+				Message:      "handle nil",
+				Body:         `This reverts "COMP-12: <fix> handle nil".`,
+				Trailers:     []cmt.Trailer{{Key: "Reverts", Value: "abc123"}},
+			},
+		},
+		{
+			"it should use unconventional subjects as the message",
+			"Update README.md",
+			cmt.Commit{
+				Type:     "revert",
+				Message:  "Update README.md",
+				Body:     `This reverts "Update README.md".`,
+				Trailers: []cmt.Trailer{{Key: "Reverts", Value: "abc123"}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			got := revertPrefill(cfg.Default().Commit(), "abc123", tc.message)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
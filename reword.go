@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/alessio/shellescape"
+)
+
+const (
+	SequenceEditor = "sequence-editor"
+	MessageEditor  = "message-editor"
+)
+
+// rewordCommit rewrites the message of a commit below HEAD by running an
+// interactive rebase which uses meteor itself as both the sequence editor,
+// to mark the commit for rewording, and the message editor, to write the new
+// message
+func rewordCommit(sha string, msg string, body string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("could not find the meteor executable: %w", err)
+	}
+
+	messageFile, err := os.CreateTemp("", "meteor-reword-*")
+	if err != nil {
+		return fmt.Errorf("could not write the new message: %w", err)
+	}
+	defer os.Remove(messageFile.Name())
+
+	message := msg
+	if body != "" {
+		message += "\n\n" + body
+	}
+	if _, err := messageFile.WriteString(message + "\n"); err != nil {
+		return fmt.Errorf("could not write the new message: %w", err)
+	}
+	if err := messageFile.Close(); err != nil {
+		return fmt.Errorf("could not write the new message: %w", err)
+	}
+
+	cmd := rewordCommand(self, sha, messageFile.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// rewordCommand returns the rebase that rewords the commit with the message
+// in messageFile, running editor as the sequence and message editor. The
+// message is only stripped of surrounding whitespace, so body lines starting
+// with "#" are kept
+func rewordCommand(editor string, sha string, messageFile string) *exec.Cmd {
+	args := append([]string{"-c", "commit.cleanup=whitespace", "rebase", "--interactive", "--autostash", "--rebase-merges"}, rebaseBase(sha)...)
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("GIT_SEQUENCE_EDITOR=%s --%s %s", shellescape.Quote(editor), SequenceEditor, sha),
+		fmt.Sprintf("GIT_EDITOR=%s --%s %s", shellescape.Quote(editor), MessageEditor, shellescape.Quote(messageFile)),
+	)
+	return cmd
+}
+
+// markForReword changes the pick of the given commit in a rebase todo list to
+// a reword
+func markForReword(todo string, sha string) (string, error) {
+	lines := strings.Split(todo, "\n")
+	found := false
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "pick" && fields[0] != "p") {
+			continue
+		}
+		if strings.HasPrefix(sha, fields[1]) {
+			lines[i] = "reword " + strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), fields[0]))
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("could not find %s in the rebase todo list", sha)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// runSequenceEditor is run by git as GIT_SEQUENCE_EDITOR during a reword
+func runSequenceEditor(sha string, todoFile string) error {
+	todo, err := os.ReadFile(todoFile)
+	if err != nil {
+		return err
+	}
+	updated, err := markForReword(string(todo), sha)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(todoFile, []byte(updated), 0644)
+}
+
+// runMessageEditor is run by git as GIT_EDITOR during a reword and replaces
+// the commit message with the one meteor prepared
+func runMessageEditor(messageFile string, commitFile string) error {
+	message, err := os.ReadFile(messageFile)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(commitFile, message, 0644)
+}
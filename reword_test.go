@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarkForReword(t *testing.T) {
+	todo := "pick 1a2b3c4 feat: first\npick 5d6e7f8 fix: second\n\n# Rebase 1a2b3c4..5d6e7f8 onto 0000000"
+
+	t.Run("it should reword the matching commit", func(t *testing.T) {
+		got, err := markForReword(todo, "5d6e7f8aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		if err != nil {
+			t.Fatalf("expected no error, but got %v", err)
+		}
+		expected := "pick 1a2b3c4 feat: first\nreword 5d6e7f8 fix: second\n\n# Rebase 1a2b3c4..5d6e7f8 onto 0000000"
+		assertEqualStrings(t, expected, got)
+	})
+	t.Run("it should error when the commit is missing", func(t *testing.T) {
+		if _, err := markForReword(todo, "9999999"); err == nil {
+			t.Errorf("expected an error, but got nil")
+		}
+	})
+}
+
+func TestRewordCommandKeepsHashLines(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "Ann")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ann@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ann")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ann@example.com")
+
+	git := func(args ...string) string {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q")
+	for _, subject := range []string{"feat: first", "fix: second"} {
+		if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte(subject), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", "a.txt")
+		git("commit", "-q", "-m", subject)
+	}
+	sha := git("rev-parse", "HEAD~1")
+
+	// the editor stands in for meteor, marking the commit and writing the
+	// prepared message as it does
+	editor := filepath.Join(dir, "editor")
+	script := "#!/bin/sh\nif [ \"$1\" = --" + SequenceEditor + " ]; then sed -i 's/^pick/reword/' \"$3\"; else cp \"$2\" \"$3\"; fi\n"
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	message := "feat: first\n\n# Migration\n\nCloses #12"
+	messageFile := filepath.Join(dir, "message")
+	if err := os.WriteFile(messageFile, []byte(message+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := rewordCommand(editor, sha, messageFile).CombinedOutput(); err != nil {
+		t.Fatalf("expected no error, but got %v\n%s", err, out)
+	}
+	assertEqualStrings(t, message, git("log", "-1", "--format=%B", "HEAD~1"))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/log"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/secrets"
+)
+
+// newSecretScanner builds the scanner from the config, or returns nil when
+// scanning is off. Invalid patterns are logged and skipped
+func newSecretScanner(c cfg.SecretScan) *secrets.Scanner {
+	if !c.IsEnabled() {
+		return nil
+	}
+	s := secrets.New()
+	for _, p := range c.Patterns {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			log.Error("Ignoring invalid secret pattern", "name", p.Name, "error", err)
+			continue
+		}
+		s.Patterns = append(s.Patterns, secrets.Pattern{Name: p.Name, Regex: re})
+	}
+	for _, allow := range c.Allow {
+		re, err := regexp.Compile(allow)
+		if err != nil {
+			log.Error("Ignoring invalid secret allow pattern", "pattern", allow, "error", err)
+			continue
+		}
+		s.Allow = append(s.Allow, re)
+	}
+	s.AllowPaths = c.AllowPaths
+	if c.Entropy != nil {
+		s.Entropy = *c.Entropy
+	}
+	return s
+}
+
+// checkSecrets scans the changes about to be committed and asks before
+// committing anything that looks like a secret. It returns whether to go
+// ahead with the commit
+func checkSecrets(config cfg.Settings, theme *huh.Theme) bool {
+	scanner := newSecretScanner(config.Secrets)
+	if scanner == nil {
+		return true
+	}
+	diff, err := getCommitDiff(all)
+	if err != nil {
+		log.Error("Skipping the secret scan", "error", err)
+		return true
+	}
+	findings := scanner.Scan(diff)
+	if len(findings) == 0 {
+		return true
+	}
+
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = f.String()
+	}
+	commitAnyway := false
+	err = huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title(catalogue.T("secrets.title")).
+				Description(catalogue.T("secrets.description", strings.Join(lines, "\n"), secrets.AllowComment)),
+			huh.NewConfirm().
+				Title(catalogue.T("secrets.confirm")).
+				Affirmative(catalogue.T("yes")).
+				Negative(catalogue.T("no")).
+				Value(&commitAnyway),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible).Run()
+	return err == nil && commitAnyway
+}
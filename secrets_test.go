@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestNewSecretScanner(t *testing.T) {
+	disabled := false
+	if s := newSecretScanner(cfg.SecretScan{Enabled: &disabled}); s != nil {
+		t.Error("expected no scanner when scanning is disabled")
+	}
+
+	entropy := 0.0
+	s := newSecretScanner(cfg.SecretScan{
+		Patterns: []cfg.SecretPattern{
+			{Name: "internal token", Regex: `itk_[a-z0-9]{8}`},
+			{Name: "broken", Regex: `(`},
+		},
+		Allow:   []string{`^itk_00000000$`, `[`},
+		Entropy: &entropy,
+	})
+	findings := s.Scan("+++ b/main.go\n@@ -1 +1,2 @@\n+itk_abcd1234\n+itk_00000000\n")
+	if len(findings) != 1 || findings[0].Secret != "itk_abcd1234" {
+		t.Errorf("got %+v, want only itk_abcd1234", findings)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+// signingFailureMarkers are parts of the errors git and the signing programs
+// print when a commit cannot be signed
+var signingFailureMarkers = []string{
+	"failed to sign the data",
+	"gpg failed",
+	"secret key not available",
+	"no secret key",
+	"no pinentry",
+	"inappropriate ioctl for device",
+	"couldn't load public key",
+	"couldn't get agent socket",
+	"ssh-keygen",
+	"gpgsm",
+}
+
+// signing describes how commits are signed
+type signing struct {
+	Enabled bool
+	// Format is git's gpg.format: openpgp, ssh or x509
+	Format  string
+	Key     string
+	Program string
+}
+
+// signArgs and noSignArgs are the git commit arguments which turn signing on
+// and off
+var (
+	signArgs   = []string{"-S", "--gpg-sign"}
+	noSignArgs = []string{"--no-gpg-sign"}
+)
+
+// addSignArg adds -S to the git commit arguments unless they already turn
+// signing on or off
+func addSignArg(args []string) []string {
+	for _, arg := range args {
+		if isSignArg(arg) || slices.Contains(noSignArgs, arg) {
+			return args
+		}
+	}
+	return append(args, "-S")
+}
+
+func isSignArg(arg string) bool {
+	return slices.Contains(signArgs, arg) || strings.HasPrefix(arg, "--gpg-sign=") || strings.HasPrefix(arg, "-S")
+}
+
+// getSigning works out whether the commit will be signed from the config,
+// the arguments and git's own settings
+func getSigning(config cfg.Settings, args []string) signing {
+	s := signing{
+		Enabled: config.Sign || getGitConfig("--bool", "commit.gpgSign") == "true",
+		Format:  getGitConfig("gpg.format"),
+		Key:     getGitConfig("user.signingKey"),
+	}
+	for _, arg := range args {
+		switch {
+		case isSignArg(arg):
+			s.Enabled = true
+			if _, key, ok := strings.Cut(arg, "="); ok {
+				s.Key = key
+			} else if len(arg) > 2 && arg[1] != '-' {
+				s.Key = arg[2:]
+			}
+		case slices.Contains(noSignArgs, arg):
+			s.Enabled = false
+		}
+	}
+	if s.Format == "" {
+		s.Format = "openpgp"
+	}
+	s.Program = getGitConfig("gpg." + s.Format + ".program")
+	if s.Program == "" && s.Format == "openpgp" {
+		s.Program = getGitConfig("gpg.program")
+	}
+	if s.Program == "" {
+		s.Program = map[string]string{"openpgp": "gpg", "ssh": "ssh-keygen", "x509": "gpgsm"}[s.Format]
+	}
+	return s
+}
+
+// String describes the signing set up for the confirm step
+func (s signing) String() string {
+	if !s.Enabled {
+		return ""
+	}
+	name := map[string]string{"openpgp": "GPG", "ssh": "SSH", "x509": "X.509"}[s.Format]
+	if s.Key == "" {
+		return catalogue.T("signing.defaultKey", name)
+	}
+	key := s.Key
+	if strings.HasPrefix(key, "key::") || len(key) > 40 {
+		// literal SSH keys are too long to show in full
+		key = key[:min(len(key), 24)] + "…"
+	}
+	return catalogue.T("signing.key", name, key)
+}
+
+// verify checks that the signing program is installed and the key can be
+// used, so that signing problems show up before the message is written
+func (s signing) verify() error {
+	if !s.Enabled {
+		return nil
+	}
+	if _, err := exec.LookPath(s.Program); err != nil {
+		return errors.New(catalogue.T("signing.notInstalled", s.Program))
+	}
+	switch s.Format {
+	case "openpgp":
+		key := s.Key
+		if key == "" {
+			key = getGitConfig("user.email")
+		}
+		if err := exec.Command(s.Program, "--list-secret-keys", key).Run(); err != nil {
+			return errors.New(catalogue.T("signing.noGPGKey", key))
+		}
+	case "ssh":
+		if s.Key == "" {
+			if getGitConfig("gpg.ssh.defaultKeyCommand") != "" {
+				return nil
+			}
+			return errors.New(catalogue.T("signing.noSSHKeySet"))
+		}
+		if strings.HasPrefix(s.Key, "key::") || strings.HasPrefix(s.Key, "ssh-") {
+			return nil
+		}
+		if _, err := os.Stat(expandHome(s.Key)); err != nil {
+			return errors.New(catalogue.T("signing.noSSHKey", s.Key))
+		}
+	case "x509":
+		out, err := exec.Command(s.Program, "--list-secret-keys").Output()
+		if err != nil || strings.TrimSpace(string(out)) == "" {
+			return errors.New(catalogue.T("signing.noX509Key"))
+		}
+	}
+	return nil
+}
+
+// hints returns suggestions for fixing signing problems
+func (s signing) hints() []string {
+	hints := []string{}
+	switch s.Format {
+	case "openpgp":
+		hints = append(hints,
+			catalogue.T("signing.gpgKey"),
+			catalogue.T("signing.gpgTTY"),
+		)
+	case "ssh":
+		hints = append(hints,
+			catalogue.T("signing.sshKey"),
+		)
+	case "x509":
+		hints = append(hints,
+			catalogue.T("signing.x509Key"),
+		)
+	}
+	return append(hints, catalogue.T("signing.unsigned"))
+}
+
+// isSigningFailure reports whether git's error output is about signing
+func isSigningFailure(stderr string) bool {
+	stderr = strings.ToLower(stderr)
+	for _, marker := range signingFailureMarkers {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
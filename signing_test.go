@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddSignArg(t *testing.T) {
+	cases := []struct {
+		Desc string
+		args []string
+		want string
+	}{
+		{"it should add -S", []string{"--no-verify"}, "--no-verify -S"},
+		{"it should keep an existing -S", []string{"-S"}, "-S"},
+		{"it should keep a key given with -S", []string{"-SABCDEF"}, "-SABCDEF"},
+		{"it should keep --gpg-sign with a key", []string{"--gpg-sign=ABCDEF"}, "--gpg-sign=ABCDEF"},
+		{"it should not sign with --no-gpg-sign", []string{"--no-gpg-sign"}, "--no-gpg-sign"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, strings.Join(addSignArg(tc.args), " "))
+		})
+	}
+}
+
+func TestIsSigningFailure(t *testing.T) {
+	cases := []struct {
+		Desc   string
+		stderr string
+		want   bool
+	}{
+		{"gpg failure", "error: gpg failed to sign the data\nfatal: failed to write commit object\n", true},
+		{"ssh failure", "error: Couldn't load public key /tmp/key: No such file or directory?\n", true},
+		{"hook failure", "husky - pre-commit hook exited with code 1\n", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualBools(t, tc.want, isSigningFailure(tc.stderr))
+		})
+	}
+}
+
+func TestSigningString(t *testing.T) {
+	cases := []struct {
+		Desc    string
+		signing signing
+		want    string
+	}{
+		{"it should be empty when not signing", signing{Format: "openpgp"}, ""},
+		{"it should name the default key", signing{Enabled: true, Format: "openpgp"}, "Signed with your default GPG key"},
+		{"it should name the key", signing{Enabled: true, Format: "ssh", Key: "~/.ssh/id_ed25519.pub"}, "Signed with the SSH key ~/.ssh/id_ed25519.pub"},
+		{"it should shorten literal keys", signing{Enabled: true, Format: "ssh", Key: "key::ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA"}, "Signed with the SSH key key::ssh-ed25519 AAAAC3N…"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, tc.signing.String())
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+
+	"github.com/stefanlogue/meteor/internal/wizard"
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+// autosquashPrefixes mark commits that are folded into another commit
+var autosquashPrefixes = []string{"fixup! ", "squash! ", "amend! "}
+
+// runSquashMessage proposes a single message for the commits on the current
+// branch, lets the user edit it in the wizard, then commits the staged
+// changes with it, such as after "git merge --squash", or prints it
+func runSquashMessage(config cfg.Settings, theme *huh.Theme, args []string) {
+	entries, args := branchCommits("squash-message", args)
+	prefill := proposeSquash(config.Commit(), config.Prefixes, entries, getBranchTicket(boardNames(config)), getGitConfig("user.email"))
+	state, err := newWizard(config, prefill).Run(wizard.Interactive{Theme: theme})
+	if err != nil {
+		fail(ErrorString, err)
+	}
+
+	rawCommitCommand, printableCommitCommand := buildCommitCommand(state.Subject, state.Body, args)
+	if !state.Confirmed {
+		commitAborted(printableCommitCommand)
+		return
+	}
+	if !hasStagedChanges() {
+		fmt.Println(strings.TrimSpace(state.Subject + "\n\n" + state.Body))
+		return
+	}
+	if err := commit(rawCommitCommand); err != nil {
+		commitFailed(printableCommitCommand, err)
+	}
+	rememberMessage(config.History, state)
+	fmt.Printf("\n%s\n\n", messages.successf("%s", catalogue.T("squash.committed")))
+}
+
+// branchCommits returns the commits on the current branch since it forked
+// from the base given as the first argument, or the default branch, along
+// with the remaining arguments
+func branchCommits(command string, args []string) ([]logEntry, []string) {
+	base := ""
+	if len(args) > 0 {
+		base = args[0]
+		args = args[1:]
+	} else {
+		base = defaultBranch()
+		if base == "" {
+			fail("Usage: meteor %s <base>", command)
+		}
+	}
+	mergeBase, err := getMergeBase(base)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	entries, err := getLog("--no-merges", "--reverse", mergeBase+"..HEAD")
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	if len(entries) == 0 {
+		fail(ErrorString, fmt.Sprintf("there are no commits since %s", base))
+	}
+	return entries, args
+}
+
+// proposeSquash builds one commit from the commits on a branch, oldest
+// first: the most common type, every scope if the commit can have that many,
+// the branch ticket, a body listing
+// each change, and everyone else who worked on them as coauthors
+func proposeSquash(c cmt.Config, prefixes []string, entries []logEntry, ticket string, email string) cmt.Commit {
+	squashed := cmt.Commit{TicketNumber: ticket}
+	typeCounts := map[string]int{}
+	firstMessage := map[string]string{}
+	subjects := []string{}
+	bodies := []string{}
+	scopes := []string{}
+	for _, e := range entries {
+		if slices.ContainsFunc(autosquashPrefixes, func(p string) bool { return strings.HasPrefix(e.Subject(), p) }) {
+			continue
+		}
+		subjects = append(subjects, e.Subject())
+		cm, err := cmt.Parse(c, e.Message)
+		if strings.TrimSpace(cm.Body) != "" {
+			bodies = append(bodies, strings.TrimSpace(cm.Body))
+		}
+		squashed.Coauthors = appendNew(squashed.Coauthors, cm.Coauthors...)
+		if email == "" || !strings.Contains(strings.ToLower(e.Author), "<"+strings.ToLower(email)+">") {
+			squashed.Coauthors = appendNew(squashed.Coauthors, e.Author)
+		}
+		if err != nil {
+			continue
+		}
+		typeCounts[cm.Type]++
+		if _, ok := firstMessage[cm.Type]; !ok {
+			firstMessage[cm.Type] = cm.Message
+		}
+		for _, scope := range cmt.SplitScopes(c, cm.Scope) {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		squashed.IsBreakingChange = squashed.IsBreakingChange || cm.IsBreakingChange
+		if squashed.TicketNumber == "" {
+			squashed.TicketNumber = cm.TicketNumber
+		}
+	}
+
+	if len(scopes) <= max(c.MaxScopes, 1) {
+		squashed.Scope = cmt.JoinScopes(c, scopes)
+	}
+	squashed.Type = dominantType(typeCounts, prefixes)
+	squashed.Message = firstMessage[squashed.Type]
+	if len(subjects) == 1 {
+		squashed.Body = strings.Join(bodies, "\n\n")
+	} else {
+		lines := make([]string, len(subjects))
+		for i, s := range subjects {
+			lines[i] = "- " + s
+		}
+		squashed.Body = strings.Join(lines, "\n")
+	}
+	if i := strings.LastIndex(squashed.TicketNumber, "-"); i > 0 {
+		squashed.Board = squashed.TicketNumber[:i]
+	}
+	return squashed
+}
+
+// dominantType returns the most common type, preferring the one configured
+// first when there is a tie
+func dominantType(counts map[string]int, prefixes []string) string {
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	rank := func(t string) int {
+		if i := slices.Index(prefixes, t); i >= 0 {
+			return i
+		}
+		return len(prefixes)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if counts[types[i]] != counts[types[j]] {
+			return counts[types[i]] > counts[types[j]]
+		}
+		if rank(types[i]) != rank(types[j]) {
+			return rank(types[i]) < rank(types[j])
+		}
+		return types[i] < types[j]
+	})
+	if len(types) == 0 {
+		return ""
+	}
+	return types[0]
+}
+
+func appendNew(list []string, items ...string) []string {
+	for _, item := range items {
+		if item != "" && item != cmt.NoCoauthors && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// boardNames returns the names of the configured boards
+func boardNames(config cfg.Settings) []string {
+	boards := make([]string, len(config.Boards))
+	for i, b := range config.Boards {
+		boards[i] = b.Value
+	}
+	return boards
+}
+
+// defaultBranch returns the branch the current one is likely to be merged
+// into, or an empty string if there isn't one
+func defaultBranch() string {
+	if out, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD").Output(); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := resolveCommit(branch); err == nil {
+			return branch
+		}
+	}
+	return ""
+}
+
+// getMergeBase returns the commit the current branch forked from base at
+func getMergeBase(base string) (string, error) {
+	out, err := exec.Command("git", "merge-base", base, "HEAD").Output()
+	if err != nil {
+		return "", errors.New("could not find where the branch forked from " + base)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// currentBranch returns the name of the checked out branch, or an empty
+// string when HEAD is detached
+func currentBranch() string {
+	out, err := exec.Command("git", "branch", "--show-current").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// getBranchTicket returns the ticket number in the current branch's name for
+// any of the boards
+func getBranchTicket(boards []string) string {
+	branch := currentBranch()
+	for _, board := range boards {
+		if checkBoardMatchesBranch(board, branch) {
+			return strings.ToUpper(strings.TrimSpace(getTicketNumberFromString(branch, board)))
+		}
+	}
+	return ""
+}
+
+// hasStagedChanges reports whether the index differs from HEAD
+func hasStagedChanges() bool {
+	return exec.Command("git", "diff", "--cached", "--quiet").Run() != nil
+}
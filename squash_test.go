@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	cmt "github.com/stefanlogue/meteor/pkg/commit"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestProposeSquash(t *testing.T) {
+	c := cmt.Config{MessageTemplate: cfg.DefaultMessageTemplate, MessageWithTicketTemplate: cfg.DefaultMessageWithTicketTemplate, ScopeDelimiter: "+", MaxScopes: 2}
+	me := "Ann <ann@example.com>"
+	entries := []logEntry{
+		{Author: me, Message: "feat(api): add the export endpoint"},
+		{Author: "Bob <bob@example.com>", Message: "fix(web): show the export button\n\nCo-authored-by: Cat <cat@example.com>"},
+		{Author: me, Message: "fixup! feat(api): add the export endpoint"},
+		{Author: me, Message: "feat(api)!: stream large exports"},
+	}
+
+	got := proposeSquash(c, []string{"feat", "fix"}, entries, "ENG-12", "ann@example.com")
+	cases := []struct {
+		Desc string
+		want string
+		got  string
+	}{
+		{"it should use the most common type", "feat", got.Type},
+		{"it should use the first message of that type", "add the export endpoint", got.Message},
+		{"it should join the scopes with the delimiter", "api+web", got.Scope},
+		{"it should use the branch ticket", "ENG-12", got.TicketNumber},
+		{"it should set the board from the ticket", "ENG", got.Board},
+		{"it should list the changes without fixups", "- feat(api): add the export endpoint\n- fix(web): show the export button\n- feat(api)!: stream large exports", got.Body},
+		{"it should credit the other authors and coauthors", "Cat <cat@example.com>,Bob <bob@example.com>", strings.Join(got.Coauthors, ",")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, tc.got)
+		})
+	}
+	assertEqualBools(t, true, got.IsBreakingChange)
+}
+
+func TestProposeSquashSingleCommit(t *testing.T) {
+	c := cmt.Config{MessageTemplate: cfg.DefaultMessageTemplate}
+	entries := []logEntry{{Author: "Ann <ann@example.com>", Message: "fix: handle empty input\n\nThe parser crashed on empty files."}}
+	got := proposeSquash(c, nil, entries, "", "ann@example.com")
+	assertEqualStrings(t, "The parser crashed on empty files.", got.Body)
+	assertEqualStrings(t, "", strings.Join(got.Coauthors, ","))
+}
+
+func TestProposeSquashScopes(t *testing.T) {
+	entries := []logEntry{
+		{Author: "Ann <ann@example.com>", Message: "feat(api+docs): add the export endpoint"},
+		{Author: "Ann <ann@example.com>", Message: "fix(web): show the export button"},
+	}
+	cases := []struct {
+		Desc      string
+		maxScopes int
+		want      string
+	}{
+		{"it should split the scopes on the delimiter", 3, "api+docs+web"},
+		{"it should leave out the scope when there are too many", 2, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			c := cmt.Config{MessageTemplate: cfg.DefaultMessageTemplate, ScopeDelimiter: "+", MaxScopes: tc.maxScopes}
+			assertEqualStrings(t, tc.want, proposeSquash(c, nil, entries, "", "ann@example.com").Scope)
+		})
+	}
+}
+
+func TestDominantType(t *testing.T) {
+	cases := []struct {
+		Desc   string
+		counts map[string]int
+		want   string
+	}{
+		{"it should pick the most common type", map[string]int{"fix": 3, "feat": 1}, "fix"},
+		{"it should break ties by the configured order", map[string]int{"fix": 2, "feat": 2}, "feat"},
+		{"it should be empty without types", map[string]int{}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, dominantType(tc.counts, []string{"feat", "fix"}))
+		})
+	}
+}
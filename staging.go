@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+)
+
+// stagingSkippingArgs are git commit arguments which mean an empty index is
+// expected or that git will stage the changes itself
+var stagingSkippingArgs = []string{"-a", "--all", "--allow-empty", "--amend", "--only", "-o", "--include", "-i"}
+
+// shouldStage reports whether meteor should check the index before the wizard
+func shouldStage(args []string) bool {
+	if all || amend {
+		return false
+	}
+	for _, arg := range args {
+		if slices.Contains(stagingSkippingArgs, arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// runStaging lets the user stage changes when nothing is staged yet and
+// returns the paths that are staged
+func runStaging(theme *huh.Theme) []string {
+	files, err := getStatus()
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	if staged := stagedPaths(files); len(staged) > 0 {
+		return staged
+	}
+
+	options := []huh.Option[string]{}
+	for _, f := range files {
+		if f.IsUnstaged() {
+			options = append(options, huh.NewOption(fmt.Sprintf("%-2s %s", strings.TrimSpace(string([]byte{f.Index, f.Worktree})), f.Path), f.Path))
+		}
+	}
+	if len(options) == 0 {
+		fail(ErrorString, "there are no changes to commit")
+	}
+
+	var selected []string
+	mode := "files"
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title(catalogue.T("staging.title")).
+				Description(catalogue.T("staging.description")).
+				Options(options...).
+				Validate(func(s []string) error {
+					if len(s) == 0 {
+						return errors.New(catalogue.T("staging.required"))
+					}
+					return nil
+				}).
+				Value(&selected),
+			huh.NewSelect[string]().
+				Title(catalogue.T("staging.mode")).
+				Options(
+					huh.NewOption(catalogue.T("staging.files"), "files"),
+					huh.NewOption(catalogue.T("staging.hunks"), "hunks"),
+				).
+				Value(&mode),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible)
+	if err := form.Run(); err != nil {
+		fail(ErrorString, err)
+	}
+
+	if mode == "hunks" {
+		err = stageHunks(selected)
+	} else {
+		err = stageFiles(selected)
+	}
+	if err != nil {
+		fail(ErrorString, err)
+	}
+
+	files, err = getStatus()
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	staged := stagedPaths(files)
+	if len(staged) == 0 {
+		fail(ErrorString, "nothing was staged")
+	}
+
+	summary := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title(catalogue.T("staging.staged")).
+				Description(strings.Join(staged, "\n")),
+		),
+	).WithTheme(theme).WithKeyMap(&keyMap.KeyMap).WithAccessible(accessible)
+	if err := summary.Run(); err != nil {
+		fail(ErrorString, err)
+	}
+
+	return staged
+}
+
+// stagedPaths returns the paths that have changes in the index
+func stagedPaths(files []fileStatus) []string {
+	paths := []string{}
+	for _, f := range files {
+		if f.IsStaged() {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// inferScope guesses the scope from the directory the staged paths have in
+// common, preferring the deepest directory named after a configured scope.
+// Nested scopes such as "api/auth" match the directories leading to it. Other
+// directory names are only used when custom scopes are allowed
+func inferScope(paths []string, scopes []string, allowCustomScopes bool) string {
+	dir := commonDir(paths)
+	if dir == "" {
+		return ""
+	}
+	segments := strings.Split(dir, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		for j := 0; j <= i; j++ {
+			if scope := strings.Join(segments[j:i+1], "/"); slices.Contains(scopes, scope) {
+				return scope
+			}
+		}
+	}
+	if !allowCustomScopes {
+		return ""
+	}
+	return segments[len(segments)-1]
+}
+
+// commonDir returns the deepest directory containing every path, or an empty
+// string if that is the repository root
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	common := strings.Split(path.Dir(paths[0]), "/")
+	for _, p := range paths[1:] {
+		segments := strings.Split(path.Dir(p), "/")
+		n := 0
+		for n < len(common) && n < len(segments) && common[n] == segments[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	dir := strings.Join(common, "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
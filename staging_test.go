@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestShouldStage(t *testing.T) {
+	cases := []struct {
+		Desc string
+		args []string
+		want bool
+	}{
+		{"it should stage with no arguments", nil, true},
+		{"it should stage with unrelated arguments", []string{"--no-verify"}, true},
+		{"it should not stage with --all", []string{"--all"}, false},
+		{"it should not stage with -a", []string{"-a"}, false},
+		{"it should not stage with --allow-empty", []string{"--allow-empty"}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualBools(t, tc.want, shouldStage(tc.args))
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	out := "M  staged.go\x00 M unstaged.go\x00?? new.go\x00R  new-name.go\x00old-name.go\x00MM both.go\x00"
+	got := parseStatus(out)
+	if len(got) != 5 {
+		t.Fatalf("expected 5 entries, got %d", len(got))
+	}
+
+	cases := []struct {
+		Desc      string
+		path      string
+		staged    bool
+		unstaged  bool
+		untracked bool
+	}{
+		{"staged file", "staged.go", true, false, false},
+		{"unstaged file", "unstaged.go", false, true, false},
+		{"untracked file", "new.go", false, true, true},
+		{"renamed file", "new-name.go", true, false, false},
+		{"partly staged file", "both.go", true, true, false},
+	}
+	for i, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.path, got[i].Path)
+			assertEqualBools(t, tc.staged, got[i].IsStaged())
+			assertEqualBools(t, tc.unstaged, got[i].IsUnstaged())
+			assertEqualBools(t, tc.untracked, got[i].IsUntracked())
+		})
+	}
+}
+
+func TestInferScope(t *testing.T) {
+	cases := []struct {
+		Desc              string
+		paths             []string
+		scopes            []string
+		allowCustomScopes bool
+		want              string
+	}{
+		{"it should use the common directory", []string{"internal/wizard/steps.go", "internal/wizard/wizard.go"}, nil, true, "wizard"},
+		{"it should use the deepest common directory", []string{"pkg/config/a.go", "pkg/commit/b.go"}, nil, true, "pkg"},
+		{"it should be empty for the repository root", []string{"main.go", "pkg/config/a.go"}, nil, true, ""},
+		{"it should prefer a configured scope", []string{"web/ui/button.tsx", "web/ui/input.tsx"}, []string{"web", "api"}, false, "web"},
+		{"it should prefer a nested scope", []string{"src/api/auth/token.go"}, []string{"api", "api/auth"}, false, "api/auth"},
+		{"it should not invent a scope when custom scopes are not allowed", []string{"docs/index.md"}, []string{"web", "api"}, false, ""},
+		{"it should invent a scope when custom scopes are allowed", []string{"docs/index.md"}, []string{"web", "api"}, true, "docs"},
+		{"it should be empty with no scopes when custom scopes are not allowed", []string{"internal/wizard/steps.go"}, nil, false, ""},
+		{"it should be empty with no paths", nil, nil, false, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Desc, func(t *testing.T) {
+			assertEqualStrings(t, tc.want, inferScope(tc.paths, tc.scopes, tc.allowCustomScopes))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+	"github.com/stefanlogue/meteor/pkg/stats"
+)
+
+// runStats reports on how the repository's history follows the message
+// templates. Any arguments are passed on to git log, such as a revision range
+// or paths
+func runStats(config cfg.Settings, args []string) {
+	period, err := stats.ParsePeriod(statsPeriod)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+	logArgs := []string{"--no-merges"}
+	if statsSince != "" {
+		logArgs = append(logArgs, "--since="+statsSince)
+	}
+	log, err := getLog(append(logArgs, args...)...)
+	if err != nil {
+		fail(ErrorString, err)
+	}
+
+	entries := make([]stats.Entry, len(log))
+	for i, e := range log {
+		entries[i] = stats.Entry{Author: e.Author, Date: e.Date, Message: e.Message}
+	}
+	report := stats.Analyse(config.Commit(), entries, period)
+	if err := report.Write(os.Stdout, stats.Format(formatFlag)); err != nil {
+		fail(ErrorString, err)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+
+	"github.com/stefanlogue/meteor/internal/checks"
+	"github.com/stefanlogue/meteor/internal/wizard"
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+// defaultTheme is the base theme used when the config does not pick one
+const defaultTheme = "catppuccin"
+
+var baseThemes = map[string]func() *huh.Theme{
+	"charm":      huh.ThemeCharm,
+	"dracula":    huh.ThemeDracula,
+	"base16":     huh.ThemeBase16,
+	"catppuccin": huh.ThemeCatppuccin,
+}
+
+// messageStyles colour the messages meteor prints around the forms
+type messageStyles struct {
+	Error   lipgloss.Style
+	Warning lipgloss.Style
+	Info    lipgloss.Style
+	Success lipgloss.Style
+}
+
+// messages is set from the config's theme once it is loaded
+var messages = newMessageStyles(cfg.Theme{})
+
+// noColor reports whether colours have been turned off with NO_COLOR, or by
+// the accessible mode
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != "" || accessible
+}
+
+// newTheme returns the forms' theme: the base theme with the configured
+// colours applied, or a theme without colours when they are turned off
+func newTheme(c cfg.Theme) *huh.Theme {
+	if noColor() {
+		return noColorTheme()
+	}
+	base := strings.ToLower(c.Base)
+	if base == "" {
+		base = defaultTheme
+	}
+	newBase, ok := baseThemes[base]
+	if !ok {
+		log.Error("Unknown theme, using the default", "theme", c.Base)
+		newBase = baseThemes[defaultTheme]
+	}
+	t := newBase()
+	for _, f := range []*huh.FieldStyles{&t.Focused, &t.Blurred} {
+		if c.Title.IsSet() {
+			f.Title = f.Title.Foreground(adaptive(c.Title))
+			f.NoteTitle = f.NoteTitle.Foreground(adaptive(c.Title))
+		}
+		if c.Description.IsSet() {
+			f.Description = f.Description.Foreground(adaptive(c.Description))
+		}
+		if c.Selector.IsSet() {
+			f.SelectSelector = f.SelectSelector.Foreground(adaptive(c.Selector))
+			f.MultiSelectSelector = f.MultiSelectSelector.Foreground(adaptive(c.Selector))
+			f.TextInput.Prompt = f.TextInput.Prompt.Foreground(adaptive(c.Selector))
+			f.TextInput.Cursor = f.TextInput.Cursor.Foreground(adaptive(c.Selector))
+		}
+		if c.Selected.IsSet() {
+			f.SelectedOption = f.SelectedOption.Foreground(adaptive(c.Selected))
+			f.SelectedPrefix = f.SelectedPrefix.Foreground(adaptive(c.Selected))
+		}
+		if c.Button.IsSet() {
+			f.FocusedButton = f.FocusedButton.Background(adaptive(c.Button))
+			f.Next = f.Next.Background(adaptive(c.Button))
+		}
+		if c.Error.IsSet() {
+			f.ErrorIndicator = f.ErrorIndicator.Foreground(adaptive(c.Error))
+			f.ErrorMessage = f.ErrorMessage.Foreground(adaptive(c.Error))
+		}
+	}
+	return t
+}
+
+// noColorTheme marks the focused field and button without colour
+func noColorTheme() *huh.Theme {
+	t := huh.ThemeBase()
+	for _, f := range []*huh.FieldStyles{&t.Focused, &t.Blurred} {
+		f.Title = f.Title.Bold(true)
+		f.NoteTitle = f.NoteTitle.Bold(true).MarginBottom(1)
+		f.FocusedButton = f.FocusedButton.UnsetForeground().UnsetBackground().Reverse(true)
+		f.BlurredButton = f.BlurredButton.UnsetForeground().UnsetBackground()
+		f.TextInput.Placeholder = f.TextInput.Placeholder.UnsetForeground().Faint(true)
+	}
+	return t
+}
+
+// newMessageStyles returns the styles for meteor's messages, which default to
+// the colours of the terminal's palette, and are plain when colours are
+// turned off
+func newMessageStyles(c cfg.Theme) messageStyles {
+	if noColor() {
+		return messageStyles{}
+	}
+	style := func(color cfg.Color, fallback string) lipgloss.Style {
+		if !color.IsSet() {
+			color = cfg.Color{Light: fallback, Dark: fallback}
+		}
+		return lipgloss.NewStyle().Foreground(adaptive(color))
+	}
+	return messageStyles{
+		Error:   style(c.Error, "1"),
+		Warning: style(c.Warning, "3"),
+		Info:    style(c.Info, "4"),
+		Success: style(c.Success, "2"),
+	}
+}
+
+// newPaneStyles returns the styles of the wizard's panes, taken from the
+// forms' theme and the message colours so that they match both
+func newPaneStyles(t *huh.Theme, m messageStyles) *wizard.Styles {
+	muted := lipgloss.NewStyle().Foreground(t.Focused.Description.GetForeground())
+	return &wizard.Styles{
+		Pane:        lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Focused.Base.GetBorderLeftForeground()).Padding(0, 1),
+		PaneTitle:   lipgloss.NewStyle().Foreground(t.Focused.Title.GetForeground()).Bold(true),
+		Help:        muted,
+		Muted:       muted,
+		Warning:     m.Warning,
+		Error:       m.Error,
+		Subject:     lipgloss.NewStyle().Bold(true),
+		Misspelling: m.Error.Copy().Underline(true),
+		DiffHeader:  lipgloss.NewStyle().Bold(true),
+		DiffHunk:    m.Info,
+		DiffAdded:   m.Success,
+		DiffRemoved: m.Error,
+	}
+}
+
+// newCheckStyles returns the styles of the checks' progress, taken from the
+// forms' theme and the message colours
+func newCheckStyles(t *huh.Theme, m messageStyles) *checks.Styles {
+	return &checks.Styles{
+		Passed:  m.Success,
+		Failed:  m.Error,
+		Muted:   lipgloss.NewStyle().Foreground(t.Focused.Description.GetForeground()),
+		Spinner: lipgloss.NewStyle().Foreground(t.Focused.SelectSelector.GetForeground()),
+	}
+}
+
+func adaptive(c cfg.Color) lipgloss.AdaptiveColor {
+	light, dark := c.Light, c.Dark
+	if light == "" {
+		light = dark
+	}
+	if dark == "" {
+		dark = light
+	}
+	return lipgloss.AdaptiveColor{Light: light, Dark: dark}
+}
+
+// paint renders each line of a message on its own, so that lines are not
+// padded to the same width
+func paint(style lipgloss.Style, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m messageStyles) errorf(format string, args ...any) string {
+	return paint(m.Error, fmt.Sprintf(format, args...))
+}
+
+func (m messageStyles) warnf(format string, args ...any) string {
+	return paint(m.Warning, fmt.Sprintf(format, args...))
+}
+
+func (m messageStyles) infof(format string, args ...any) string {
+	return paint(m.Info, fmt.Sprintf(format, args...))
+}
+
+func (m messageStyles) successf(format string, args ...any) string {
+	return paint(m.Success, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	cfg "github.com/stefanlogue/meteor/pkg/config"
+)
+
+func TestNewTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	title := cfg.Color{Light: "#000000", Dark: "#FFFFFF"}
+	theme := newTheme(cfg.Theme{Base: "Dracula", Title: title})
+	assertEqualBools(t, true, theme.Focused.Title.GetForeground() == lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"})
+	assertEqualBools(t, true, theme.Blurred.Title.GetForeground() == lipgloss.AdaptiveColor{Light: "#000000", Dark: "#FFFFFF"})
+
+	t.Setenv("NO_COLOR", "1")
+	theme = newTheme(cfg.Theme{Base: "dracula"})
+	assertEqualBools(t, true, theme.Focused.FocusedButton.GetReverse())
+	assertEqualBools(t, true, theme.Focused.FocusedButton.GetBackground() == lipgloss.NoColor{})
+}
+
+func TestNewMessageStyles(t *testing.T) {
+	styles := newMessageStyles(cfg.Theme{Error: cfg.Color{Dark: "204"}})
+	assertEqualBools(t, true, styles.Error.GetForeground() == lipgloss.AdaptiveColor{Light: "204", Dark: "204"})
+	assertEqualBools(t, true, styles.Warning.GetForeground() == lipgloss.AdaptiveColor{Light: "3", Dark: "3"})
+}
+
+func TestNewPaneStyles(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	c := cfg.Theme{Title: cfg.Color{Dark: "99"}, Warning: cfg.Color{Dark: "214"}}
+	styles := newPaneStyles(newTheme(c), newMessageStyles(c))
+	assertEqualBools(t, true, styles.PaneTitle.GetForeground() == lipgloss.AdaptiveColor{Light: "99", Dark: "99"})
+	assertEqualBools(t, true, styles.Warning.GetForeground() == lipgloss.AdaptiveColor{Light: "214", Dark: "214"})
+
+	t.Setenv("NO_COLOR", "1")
+	styles = newPaneStyles(newTheme(c), newMessageStyles(c))
+	for _, style := range []lipgloss.Style{styles.PaneTitle, styles.Muted, styles.Warning, styles.DiffAdded} {
+		assertEqualBools(t, true, style.GetForeground() == lipgloss.NoColor{})
+	}
+	assertEqualBools(t, true, styles.Pane.GetBorderLeftForeground() == lipgloss.NoColor{})
+}
+
+func TestPaint(t *testing.T) {
+	// lines are rendered separately so that they are not padded
+	assertEqualStrings(t, "a\n\nlonger line", paint(lipgloss.NewStyle(), "a\n\nlonger line"))
+}